@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"bytes"
+	"strings"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/ascii"
+)
+
+const attrRefSet = `set`
+
+// applyAttributeMissing apply the document attributes "attribute-missing"
+// and "attribute-undefined" to each attribute reference in content,
+//
+//   - "skip" leave the reference as is,
+//   - "drop" remove the reference,
+//   - "drop-line" remove the line that contains the reference,
+//   - "warn" leave the reference as is and report it as Diagnostic.
+//
+// The "attribute-undefined" is applied only to "{set:NAME!}", where
+// "drop-line" remove the line after the attribute NAME unset.
+//
+// The file and lineNum is the path of file and the line number of the first
+// line in content inside the file, or zero if its unknown.
+// They are used to report the missing attribute as Diagnostic.
+// If skipPass is true, the references inside the inline passthrough are
+// ignored.
+// The isDropped is true if one of the line in content is removed.
+func (doc *Document) applyAttributeMissing(content []byte, file string, lineNum int, skipPass bool) (out []byte, isDropped bool) {
+	if bytes.IndexByte(content, '{') < 0 {
+		return content, false
+	}
+
+	var (
+		lines = bytes.Split(content, []byte("\n"))
+
+		line     []byte
+		x        int
+		n        int
+		nwritten int
+		isDrop   bool
+	)
+	out = make([]byte, 0, len(content))
+	for x, line = range lines {
+		n = 0
+		if lineNum > 0 {
+			n = lineNum + x
+		}
+		line, isDrop = doc.applyAttributeMissingLine(line, file, n, skipPass)
+		if isDrop {
+			isDropped = true
+			continue
+		}
+		if nwritten > 0 {
+			out = append(out, '\n')
+		}
+		out = append(out, line...)
+		nwritten++
+	}
+	return out, isDropped
+}
+
+func (doc *Document) applyAttributeMissingLine(line []byte, file string, lineNum int, skipPass bool) (out []byte, isDrop bool) {
+	var (
+		missing   = doc.Attributes.Entry[docAttrAttributeMissing]
+		undefined = doc.Attributes.Entry[docAttrAttributeUndefined]
+
+		name string
+		x    int
+		end  int
+		c    byte
+	)
+	out = make([]byte, 0, len(line))
+	for x < len(line) {
+		c = line[x]
+		if c == '\\' && x+1 < len(line) {
+			out = append(out, line[x:x+2]...)
+			x += 2
+			continue
+		}
+		if skipPass {
+			end = indexPassthroughEnd(line[x:])
+			if end > 0 {
+				out = append(out, line[x:x+end]...)
+				x += end
+				continue
+			}
+		}
+		if c != '{' {
+			out = append(out, c)
+			x++
+			continue
+		}
+		end = bytes.IndexByte(line[x+1:], '}')
+		if end < 0 {
+			out = append(out, line[x:]...)
+			break
+		}
+		name = string(line[x+1 : x+1+end])
+		if !isAttrRefName(name) {
+			out = append(out, c)
+			x++
+			continue
+		}
+
+		var ref = line[x : x+end+2]
+		x += len(ref)
+
+		if isAttrRefUnset(name) && undefined != attrValueDrop {
+			doc.setRef(name)
+			return nil, true
+		}
+		if doc.isAttrRefDefined(name) {
+			out = append(out, ref...)
+			continue
+		}
+		switch missing {
+		case attrValueDrop:
+		case attrValueDropLine:
+			return nil, true
+		case attrValueWarn:
+			doc.addDiagnostic(file, lineNum,
+				`skipping reference to missing attribute: %s`, name)
+			out = append(out, ref...)
+		default:
+			out = append(out, ref...)
+		}
+	}
+	return out, false
+}
+
+// indexPassthroughEnd return the length of inline passthrough at the
+// beginning of text, "+" TEXT "+", "++" TEXT "++", "+++" TEXT "+++", or
+// "pass:" SUBS "[" TEXT "]".
+// It will return 0 if text is not started with passthrough.
+func indexPassthroughEnd(text []byte) int {
+	var idx int
+
+	if bytes.HasPrefix(text, []byte(macroPass+`:`)) {
+		_, idx = parseMacroPass(text[len(macroPass):])
+		if idx == 0 {
+			return 0
+		}
+		// The idx from parseMacroPass count one character after
+		// the "]", since its relative to the ":".
+		return len(macroPass) + idx - 1
+	}
+
+	var n int
+	for n < len(text) && n < 3 && text[n] == '+' {
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+
+	var token = text[:n]
+
+	idx = bytes.Index(text[n:], token)
+	if idx <= 0 {
+		return 0
+	}
+	return n + idx + n
+}
+
+// isAttrRefDefined return true if the attribute reference name is
+// defined, or its a counter or set reference.
+func (doc *Document) isAttrRefDefined(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if strings.Contains(name, `:`) {
+		return true
+	}
+
+	var ok bool
+	_, ok = _attrRef[name]
+	if ok {
+		return true
+	}
+	_, ok = doc.Attributes.Entry[name]
+	return ok
+}
+
+// isAttrRefName return true if name is valid attribute name, a word
+// character followed by word characters or "-", or a counter or set
+// reference.
+func isAttrRefName(name string) bool {
+	var (
+		fields = strings.SplitN(name, `:`, 2)
+		c      byte
+		x      int
+	)
+	if len(fields) == 2 {
+		switch strings.ToLower(fields[0]) {
+		case attrRefCounter, attrRefCounter2, attrRefSet:
+			return len(fields[1]) > 0
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	for x = 0; x < len(name); x++ {
+		c = name[x]
+		if ascii.IsAlnum(c) || c == '_' {
+			continue
+		}
+		if x > 0 && c == '-' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// isAttrRefUnset return true if the ref is "set:NAME!".
+func isAttrRefUnset(ref string) bool {
+	return strings.HasPrefix(strings.ToLower(ref), attrRefSet+`:`) &&
+		strings.HasSuffix(ref, `!`)
+}
+
+// setRef set or unset the document attribute from the reference,
+//
+//	"set:" NAME [ ":" VALUE ]
+//
+//	"set:" NAME "!"
+//
+// It will return false if the ref is not set reference.
+func (doc *Document) setRef(ref string) bool {
+	var fields = strings.SplitN(ref, `:`, 3)
+	if len(fields) < 2 {
+		return false
+	}
+	if strings.ToLower(strings.TrimSpace(fields[0])) != attrRefSet {
+		return false
+	}
+
+	var (
+		key   = strings.ToLower(strings.TrimSpace(fields[1]))
+		value string
+	)
+	if len(key) == 0 || key == `!` {
+		return false
+	}
+	if len(fields) == 3 {
+		value = fields[2]
+	}
+	_ = doc.setAttribute(key, value)
+	return true
+}
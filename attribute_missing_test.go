@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"bytes"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+)
+
+func TestAttributeMissing_warn(t *testing.T) {
+	var (
+		content = []byte(`= Title
+:attribute-missing: warn
+:x: {nope}
+
+Hello {name}.
+
+Line one
+has {missing} ref.
+
+[source,subs="attributes+"]
+----
+code {incode}
+----
+
+pass:[{inpass}]{afterpass}
+`)
+		doc = Parse(content)
+		buf bytes.Buffer
+	)
+
+	var err = doc.ToHTMLEmbedded(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = []Diagnostic{{
+		Line:    3,
+		Message: `skipping reference to missing attribute: nope`,
+	}, {
+		Line:    5,
+		Message: `skipping reference to missing attribute: name`,
+	}, {
+		Line:    8,
+		Message: `skipping reference to missing attribute: missing`,
+	}, {
+		Line:    12,
+		Message: `skipping reference to missing attribute: incode`,
+	}, {
+		Line:    15,
+		Message: `skipping reference to missing attribute: afterpass`,
+	}}
+	test.Assert(t, `Diagnostics`, exp, doc.Diagnostics)
+}
+
+func TestAttributeMissing_warnInclude(t *testing.T) {
+	var (
+		fixtures = map[string]string{
+			`a.adoc`: "A1.\nA2.\nA3.\n",
+			`b.adoc`: "B1.\n\nB3 {inb}.\n",
+		}
+		opts = Options{
+			IncludeResolver: func(target string, _ map[string]string, _ string) ([]byte, error) {
+				return []byte(fixtures[target]), nil
+			},
+		}
+		content = []byte(`= Title
+:attribute-missing: warn
+
+include::a.adoc[]
+
+Text {afterinclude}.
+
+include::b.adoc[]
+
+include::{intarget}.adoc[]
+`)
+		doc = ParseWithOptions(content, opts)
+		buf bytes.Buffer
+	)
+
+	var err = doc.ToHTMLEmbedded(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = []Diagnostic{{
+		Line:    6,
+		Message: `skipping reference to missing attribute: afterinclude`,
+	}, {
+		File:    `b.adoc`,
+		Line:    3,
+		Message: `skipping reference to missing attribute: inb`,
+	}, {
+		Line:    10,
+		Message: `skipping reference to missing attribute: intarget`,
+	}}
+	test.Assert(t, `Diagnostics`, exp, doc.Diagnostics)
+}
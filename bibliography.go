@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"bytes"
+	"strings"
+)
+
+// reference contains the target of cross reference or the ID of
+// bibliography entry, and the line number in the document content where
+// its found.
+type reference struct {
+	id      string
+	lineNum int
+}
+
+// parseBibliographyAnchor parse and remove the bibliography anchor at the
+// beginning of list item,
+//
+//	BIBLIOGRAPHY_ANCHOR = "[[[" REF_ID [ "," LABEL ] "]]]"
+//
+// The REF_ID is registered as anchor with "[LABEL]" as its cross reference
+// text, or "[REF_ID]" if LABEL is empty.
+// It will return the element for anchor and its label, or nil if the list
+// item does not start with bibliography anchor.
+func (el *element) parseBibliographyAnchor(doc *Document) (anchor *element) {
+	if !bytes.HasPrefix(el.raw, []byte(`[[[`)) {
+		return nil
+	}
+
+	var x = bytes.Index(el.raw, []byte(`]]]`))
+	if x < 0 {
+		return nil
+	}
+
+	var id, label, _ = strings.Cut(string(el.raw[3:x]), `,`)
+
+	id = strings.TrimSpace(id)
+	if !isValidID([]byte(id)) {
+		return nil
+	}
+	label = strings.TrimSpace(label)
+	if len(label) == 0 {
+		label = id
+	}
+	label = `[` + string(htmlSubsChar([]byte(label))) + `]`
+
+	anchor = &element{
+		elementAttribute: elementAttribute{
+			ID: doc.registerAnchor(id, label),
+		},
+		kind: elKindInlineID,
+	}
+	anchor.addChild(&element{
+		kind: elKindText,
+		raw:  []byte(label + ` `),
+	})
+	doc.bibRefs = append(doc.bibRefs, reference{
+		id:      anchor.ID,
+		lineNum: el.lineNum,
+	})
+
+	el.raw = bytes.TrimLeft(el.raw[x+3:], " \t")
+	return anchor
+}
+
+// postParseListBibliography parse the list item in bibliography list and
+// its anchor.
+func (el *element) postParseListBibliography(doc *Document) {
+	var (
+		item = el.child
+
+		anchor    *element
+		container *element
+	)
+	for ; item != nil; item = item.next {
+		if item.kind != elKindListUnorderedItem {
+			continue
+		}
+		anchor = item.parseBibliographyAnchor(doc)
+		item.parseInlineMarkup(doc, elKindInlineParagraph)
+		if anchor == nil {
+			continue
+		}
+		container = item.child
+		if container == nil || container.kind != elKindInlineParagraph {
+			container = &element{
+				kind: elKindInlineParagraph,
+			}
+			item.prependChild(container)
+		}
+		if len(container.raw) > 0 {
+			// Move the text before the first inline markup
+			// after the anchor.
+			container.prependChild(&element{
+				kind: elKindText,
+				raw:  container.raw,
+			})
+			container.raw = nil
+		}
+		container.prependChild(anchor)
+	}
+}
+
+// checkReferences report the cross references that does not match with
+// any anchors or section titles, and the bibliography entries that are not
+// cited.
+func (doc *Document) checkReferences() {
+	var (
+		cited = map[string]bool{}
+
+		ref     reference
+		href    string
+		file    string
+		lineNum int
+		ok      bool
+	)
+	for _, ref = range doc.xrefs {
+		href = ref.id
+		cited[href] = true
+		if strings.IndexByte(href, '#') >= 0 || strings.HasSuffix(href, `.adoc`) {
+			// Inter-document cross reference.
+			continue
+		}
+		_, ok = doc.anchors[href]
+		if ok {
+			continue
+		}
+		_, ok = doc.titleID[href]
+		if ok {
+			continue
+		}
+		file, lineNum = doc.lineSource(ref.lineNum)
+		doc.addDiagnostic(file, lineNum,
+			`possible invalid reference: %s`, href)
+	}
+	for _, ref = range doc.bibRefs {
+		if !cited[ref.id] {
+			file, lineNum = doc.lineSource(ref.lineNum)
+			doc.addDiagnostic(file, lineNum,
+				`bibliography entry %q is not cited`, ref.id)
+		}
+	}
+}
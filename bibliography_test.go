@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+)
+
+func TestDocumentCheckReferences(t *testing.T) {
+	var (
+		content = `== Intro
+
+See <<pp>>, <<notexist>>, <<Intro>>, and <<other.adoc#top>>.
+
+[bibliography]
+== References
+
+* [[[pp]]] The Pragmatic Programmer.
+* [[[unused]]] Not cited.
+`
+		exp = []Diagnostic{{
+			Line:    3,
+			Message: `possible invalid reference: notexist`,
+		}, {
+			Line:    9,
+			Message: `bibliography entry "unused" is not cited`,
+		}}
+
+		doc = Parse([]byte(content))
+	)
+
+	test.Assert(t, `Diagnostics`, exp, doc.Diagnostics)
+}
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const classNamePartIntro = `partintro`
+
+// isBook return true if the document attribute "doctype" is "book".
+func (doc *Document) isBook() bool {
+	return doc.Attributes.Entry[docAttrDocType] == docAttrValueBook
+}
+
+// partCaption return the caption for the next part, for example
+// "Part I: ".
+// It will return empty string if the attribute "partnums" is not set.
+func (doc *Document) partCaption() string {
+	var _, ok = doc.Attributes.Entry[docAttrPartNums]
+	if !ok {
+		return ``
+	}
+
+	doc.counterPart++
+
+	var (
+		num          = romanNumeral(doc.counterPart)
+		signifier, _ = doc.Attributes.Entry[docAttrPartSignifier]
+	)
+	if len(signifier) == 0 {
+		return num + `: `
+	}
+	return fmt.Sprintf(`%s %s: `, signifier, num)
+}
+
+// chapterCaption return the caption for chapter with its section number,
+// for example "Chapter 1. ".
+// It will return empty string if the attribute "chapter-signifier" is not
+// set.
+func (doc *Document) chapterCaption(sectnums *sectionCounters) string {
+	var signifier, _ = doc.Attributes.Entry[docAttrChapterSignifier]
+	if len(signifier) == 0 {
+		return ``
+	}
+	return signifier + ` ` + sectnums.String()
+}
+
+// postParseParts wrap the content of each part before its first chapter
+// inside the open block with role "partintro".
+func (doc *Document) postParseParts() {
+	var (
+		part  *element
+		first *element
+		last  *element
+		intro *element
+	)
+	for part = doc.content.child; part != nil; part = part.next {
+		if part.kind != elKindSectionL0 {
+			continue
+		}
+		first = part.child
+		if first == nil || first.isSection() {
+			continue
+		}
+		if first.hasStyle(styleSectionPartIntroduction) {
+			first.addRole(classNamePartIntro)
+			continue
+		}
+
+		intro = &element{
+			kind:   elKindBlockOpen,
+			parent: part,
+			child:  first,
+		}
+		intro.addRole(classNamePartIntro)
+
+		for last = first; ; last = last.next {
+			last.parent = intro
+			if last.next == nil || last.next.isSection() {
+				break
+			}
+		}
+		intro.next = last.next
+		if intro.next != nil {
+			intro.next.prev = intro
+		}
+		last.next = nil
+		part.child = intro
+	}
+}
+
+// isSection return true if the element is section level 1 to 5.
+func (el *element) isSection() bool {
+	return el.kind >= elKindSectionL1 && el.kind <= elKindSectionL5
+}
+
+func htmlWriteSectionPart(doc *Document, el *element, out io.Writer) {
+	fmt.Fprintf(out, "\n<h1 id=%q class=\"sect0\">%s", el.ID, el.caption)
+	el.title.toHTML(doc, out)
+	fmt.Fprint(out, "</h1>")
+}
+
+// htmlWriteToCBook write the table of contents of book.
+// If the book has parts, the parts and the chapters before the first part
+// are listed in one "sectlevel0" list.
+func htmlWriteToCBook(doc *Document, out io.Writer) {
+	var (
+		el       *element
+		hasParts bool
+	)
+	for el = doc.content.child; el != nil; el = el.next {
+		if el.kind == elKindSectionL0 {
+			hasParts = true
+			break
+		}
+	}
+	if !hasParts {
+		if doc.content.child != nil {
+			// Start from level -1 to include the chapters at
+			// level 1.
+			htmlWriteToC(doc, doc.content.child, out, -1)
+		}
+		return
+	}
+
+	fmt.Fprint(out, "\n<ul class=\"sectlevel0\">")
+	for el = doc.content.child; el != nil; el = el.next {
+		if el.kind != elKindSectionL0 && !el.isSection() {
+			continue
+		}
+		if el.style&styleSectionDiscrete > 0 || el.level > doc.TOCLevel {
+			continue
+		}
+		htmlWriteToCItem(doc, el, out)
+		if el.child != nil {
+			htmlWriteToC(doc, el.child, out, el.level)
+		}
+		fmt.Fprint(out, "</li>")
+	}
+	fmt.Fprint(out, "\n</ul>\n")
+}
+
+// htmlWriteTitlePage write the document title, sub title, authors, and
+// revision as title page of book.
+func htmlWriteTitlePage(doc *Document, out io.Writer) {
+	var _, ok = doc.Attributes.Entry[docAttrNoTitle]
+
+	fmt.Fprint(out, "\n<div class=\"title-page\">")
+	if !ok && doc.Title.el != nil {
+		fmt.Fprintf(out, "\n<h1>%s</h1>", doc.Title.Main)
+		if len(doc.Title.Sub) > 0 {
+			fmt.Fprintf(out, "\n<h2 class=\"subtitle\">%s</h2>", doc.Title.Sub)
+		}
+	}
+	htmlWriteHeaderDetails(doc, out)
+	fmt.Fprint(out, "\n</div>")
+}
+
+// romanNumeral return the upper case roman numeral of n.
+func romanNumeral(n int) string {
+	var (
+		values  = []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+		symbols = []string{`M`, `CM`, `D`, `CD`, `C`, `XC`, `L`, `XL`, `X`, `IX`, `V`, `IV`, `I`}
+
+		sb strings.Builder
+		x  int
+	)
+	for x = 0; x < len(values); x++ {
+		for n >= values[x] {
+			sb.WriteString(symbols[x])
+			n -= values[x]
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+)
+
+func TestRomanNumeral(t *testing.T) {
+	type testCase struct {
+		exp string
+		n   int
+	}
+
+	var cases = []testCase{{
+		n:   1,
+		exp: `I`,
+	}, {
+		n:   4,
+		exp: `IV`,
+	}, {
+		n:   9,
+		exp: `IX`,
+	}, {
+		n:   14,
+		exp: `XIV`,
+	}, {
+		n:   1994,
+		exp: `MCMXCIV`,
+	}}
+
+	var c testCase
+	for _, c = range cases {
+		test.Assert(t, c.exp, c.exp, romanNumeral(c.n))
+	}
+}
+
+func TestParseSectionL0InArticle(t *testing.T) {
+	var (
+		doc = Parse([]byte("= Title\n\n= Part\n\n== Chapter\n"))
+		exp = []Diagnostic{{
+			Line:    3,
+			Message: `level 0 sections can only be used when doctype is book: = Part`,
+		}}
+	)
+	test.Assert(t, `Diagnostics`, exp, doc.Diagnostics)
+}
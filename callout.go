@@ -0,0 +1,309 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/ascii"
+)
+
+// List of comment prefix that can be placed before the callout marker in
+// listing block, for example "fmt.Println() // <1>".
+var calloutCommentPrefixes = [][]byte{
+	[]byte(`//`),
+	[]byte(`#`),
+	[]byte(`--`),
+	[]byte(`;;`),
+}
+
+// callout contains the marker "<N>" in the listing block or the reference
+// to the markers in the callout list item.
+type callout struct {
+	// id is the HTML ID of the marker in the listing block, for example
+	// "CO1-1".
+	id string
+
+	// ref is the HTML ID of the pair, the callout list item for marker
+	// or the marker for callout list item, for example "CL1-1".
+	ref string
+
+	// line is the index of line in listing block where the marker
+	// found.
+	line int
+
+	// num is the callout number.
+	num int
+}
+
+// calloutState contains the listing block with callouts that has not been
+// matched by the callout list.
+type calloutState struct {
+	block *element
+
+	// used contains the callout numbers that has been described in the
+	// callout list.
+	used map[int]bool
+
+	// index is the counter of listing blocks with callouts in the
+	// document, start from 1.
+	index int
+}
+
+// parseCallouts find and remove the callout markers at the end of each line
+// in the listing block,
+//
+//	CALLOUTS = [ COMMENT_PREFIX [ WSP ] ] CALLOUT *( [ WSP ] CALLOUT )
+//
+//	CALLOUT  = "<" ( 1*DIGIT / "." ) ">" / "<!--" ( 1*DIGIT / "." ) "-->"
+//
+// The el.raw must be already escaped, so the "<" and ">" are in the form
+// of "&lt;" and "&gt;".
+// The marker "<.>" is numbered automatically, start from 1.
+// The marker that is prefixed with "\" is not a callout, the backslash is
+// removed.
+func (el *element) parseCallouts(doc *Document) {
+	var (
+		lines   = bytes.Split(el.raw, []byte{'\n'})
+		autoNum int
+		seq     int
+
+		line []byte
+		nums []int
+		x    int
+		num  int
+	)
+
+	for x, line = range lines {
+		line, nums = parseCalloutMarkers(line)
+		if len(nums) == 0 {
+			lines[x] = line
+			continue
+		}
+		if el.callouts == nil {
+			doc.checkCallouts()
+			doc.callout.index++
+			doc.callout.block = el
+			doc.callout.used = map[int]bool{}
+		}
+		for _, num = range nums {
+			if num == 0 {
+				autoNum++
+				num = autoNum
+			}
+			seq++
+			el.callouts = append(el.callouts, callout{
+				id:   fmt.Sprintf(`CO%d-%d`, doc.callout.index, seq),
+				ref:  fmt.Sprintf(`CL%d-%d`, doc.callout.index, num),
+				line: x,
+				num:  num,
+			})
+		}
+		lines[x] = line
+	}
+	el.raw = bytes.Join(lines, []byte{'\n'})
+}
+
+// parseCalloutMarkers parse and remove the callout markers at the end of
+// line.
+// The marker "<.>" is returned as number 0.
+func parseCalloutMarkers(line []byte) (got []byte, nums []int) {
+	var (
+		rest = line
+		num  int
+		ok   bool
+	)
+
+	for {
+		var trimmed = bytes.TrimRight(rest, " \t")
+
+		got, num, ok = cutCalloutMarker(trimmed)
+		if !ok {
+			break
+		}
+		if len(got) > 0 && got[len(got)-1] == '\\' {
+			// Escaped marker, "\<1>".
+			if len(nums) == 0 {
+				return append(got[:len(got)-1:len(got)-1], trimmed[len(got):]...), nil
+			}
+			break
+		}
+		nums = append([]int{num}, nums...)
+		rest = got
+	}
+	if len(nums) == 0 {
+		return line, nil
+	}
+
+	var (
+		trimmed = bytes.TrimRight(rest, " \t")
+		prefix  []byte
+	)
+	for _, prefix = range calloutCommentPrefixes {
+		if bytes.HasSuffix(trimmed, prefix) {
+			rest = trimmed[:len(trimmed)-len(prefix)]
+			break
+		}
+	}
+	return rest, nums
+}
+
+// cutCalloutMarker remove one callout marker at the end of line.
+func cutCalloutMarker(line []byte) (got []byte, num int, ok bool) {
+	var (
+		begin = []byte(`&lt;`)
+		end   = []byte(`&gt;`)
+	)
+
+	if !bytes.HasSuffix(line, end) {
+		return line, 0, false
+	}
+
+	var (
+		x     = bytes.LastIndex(line, begin)
+		inner []byte
+	)
+	if x < 0 {
+		return line, 0, false
+	}
+	inner = line[x+len(begin) : len(line)-len(end)]
+
+	if bytes.HasPrefix(inner, []byte(`!--`)) && bytes.HasSuffix(inner, []byte(`--`)) &&
+		len(inner) > 5 {
+		inner = inner[3 : len(inner)-2]
+	}
+
+	num, ok = parseCalloutNumber(inner)
+	if !ok {
+		return line, 0, false
+	}
+	return line[:x], num, true
+}
+
+// parseCalloutNumber parse the callout number, either 1*DIGIT or ".".
+// The "." is returned as 0.
+func parseCalloutNumber(raw []byte) (num int, ok bool) {
+	if len(raw) == 1 && raw[0] == '.' {
+		return 0, true
+	}
+	if len(raw) == 0 || !ascii.IsDigits(raw) {
+		return 0, false
+	}
+
+	var err error
+
+	num, err = strconv.Atoi(string(raw))
+	if err != nil || num <= 0 {
+		return 0, false
+	}
+	return num, true
+}
+
+// isLineCalloutItem return true if the line is callout list item,
+//
+//	CALLOUT_ITEM = "<" ( 1*DIGIT / "." ) ">" 1*WSP TEXT
+func isLineCalloutItem(line []byte) bool {
+	var _, _, ok = parseCalloutItem(line)
+	return ok
+}
+
+// parseCalloutItem return the callout number and the text of callout list
+// item.
+// The "<.>" is returned as number 0.
+func parseCalloutItem(line []byte) (num int, text []byte, ok bool) {
+	if len(line) < 5 || line[0] != '<' {
+		return 0, nil, false
+	}
+
+	var x = bytes.IndexByte(line, '>')
+	if x < 2 || x+1 >= len(line) {
+		return 0, nil, false
+	}
+	if line[x+1] != ' ' && line[x+1] != '\t' {
+		return 0, nil, false
+	}
+
+	num, ok = parseCalloutNumber(line[1:x])
+	if !ok {
+		return 0, nil, false
+	}
+	text = bytes.TrimSpace(line[x+1:])
+	if len(text) == 0 {
+		return 0, nil, false
+	}
+	return num, text, true
+}
+
+// checkCallouts report the callout markers in the last listing block that
+// does not have item in the callout list.
+func (doc *Document) checkCallouts() {
+	if doc.callout.block == nil {
+		return
+	}
+
+	var (
+		reported = map[int]bool{}
+
+		co      callout
+		file    string
+		lineNum int
+	)
+	for _, co = range doc.callout.block.callouts {
+		if doc.callout.used[co.num] || reported[co.num] {
+			continue
+		}
+		file, lineNum = doc.lineSource(doc.callout.block.lineNum + co.line)
+		doc.addDiagnostic(file, lineNum,
+			`callout <%d> does not have item in callout list`, co.num)
+		reported[co.num] = true
+	}
+	doc.callout.block = nil
+	doc.callout.used = nil
+}
+
+// htmlCallouts return the content of listing block with HTML callout
+// markers.
+func (el *element) htmlCallouts(content []byte) []byte {
+	if len(el.callouts) == 0 {
+		return content
+	}
+
+	var (
+		lines = bytes.Split(content, []byte{'\n'})
+		out   bytes.Buffer
+
+		line []byte
+		co   callout
+		x    int
+		y    int
+	)
+
+	for x, line = range lines {
+		if x > 0 {
+			out.WriteByte('\n')
+		}
+		out.Write(line)
+		for ; y < len(el.callouts); y++ {
+			co = el.callouts[y]
+			if co.line != x {
+				break
+			}
+			if y > 0 && el.callouts[y-1].line == x {
+				out.WriteByte(' ')
+			}
+			htmlWriteCallout(&out, co)
+		}
+	}
+	return out.Bytes()
+}
+
+// htmlWriteCallout write the callout marker for listing block.
+func htmlWriteCallout(out io.Writer, co callout) {
+	fmt.Fprintf(out,
+		`<a id=%q href="#%s"><i class="conum" data-value="%d"></i><b>(%d)</b></a>`,
+		co.id, co.ref, co.num, co.num)
+}
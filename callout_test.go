@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+)
+
+func TestParseCalloutMarkers(t *testing.T) {
+	type testCase struct {
+		line    string
+		expLine string
+		expNums []int
+	}
+
+	var cases = []testCase{{
+		line:    `no callout`,
+		expLine: `no callout`,
+	}, {
+		line:    `x := 1 &lt;1&gt;`,
+		expLine: `x := 1 `,
+		expNums: []int{1},
+	}, {
+		line:    `x := 1 // &lt;1&gt; &lt;2&gt;`,
+		expLine: `x := 1 `,
+		expNums: []int{1, 2},
+	}, {
+		line:    `x := 1 #&lt;.&gt;`,
+		expLine: `x := 1 `,
+		expNums: []int{0},
+	}, {
+		line:    `&lt;!--3--&gt;`,
+		expLine: ``,
+		expNums: []int{3},
+	}, {
+		line:    `x := \&lt;1&gt;`,
+		expLine: `x := &lt;1&gt;`,
+	}, {
+		line:    `a &lt;b&gt;`,
+		expLine: `a &lt;b&gt;`,
+	}}
+
+	var (
+		c    testCase
+		got  []byte
+		nums []int
+	)
+	for _, c = range cases {
+		got, nums = parseCalloutMarkers([]byte(c.line))
+		test.Assert(t, c.line, c.expLine, string(got))
+		test.Assert(t, c.line+`: nums`, c.expNums, nums)
+	}
+}
+
+func TestCalloutsDiagnostics(t *testing.T) {
+	type testCase struct {
+		desc    string
+		content string
+		exp     []Diagnostic
+	}
+
+	var cases = []testCase{{
+		desc: `marker without list`,
+		content: `----
+a <1>
+----`,
+		exp: []Diagnostic{{
+			Line:    2,
+			Message: `callout <1> does not have item in callout list`,
+		}},
+	}, {
+		desc: `marker without list after paragraph`,
+		content: `Paragraph.
+
+----
+a
+b <1>
+----`,
+		exp: []Diagnostic{{
+			Line:    5,
+			Message: `callout <1> does not have item in callout list`,
+		}},
+	}, {
+		desc: `item without marker`,
+		content: `----
+a <1>
+----
+<1> One.
+<2> Two.`,
+		exp: []Diagnostic{{
+			Line:    5,
+			Message: `no callout found for <2>`,
+		}},
+	}, {
+		desc:    `list without listing`,
+		content: `<1> One.`,
+		exp: []Diagnostic{{
+			Line:    1,
+			Message: `no callout found for <1>`,
+		}},
+	}}
+
+	var (
+		c   testCase
+		doc *Document
+	)
+	for _, c = range cases {
+		doc = Parse([]byte(c.content))
+		test.Assert(t, c.desc, c.exp, doc.Diagnostics)
+	}
+}
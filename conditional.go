@@ -0,0 +1,291 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// List of conditional directive names.
+const (
+	directiveEndif  = `endif`
+	directiveIfdef  = `ifdef`
+	directiveIfeval = `ifeval`
+	directiveIfndef = `ifndef`
+)
+
+// conditional contains the state of preprocessor conditional directive
+// ("ifdef", "ifndef", or "ifeval") that has not been closed by "endif".
+type conditional struct {
+	name   string
+	target string
+
+	// file and lineNum is the location of directive in the document,
+	// for reporting the directive that is not closed.
+	file    string
+	lineNum int
+
+	// skip is true if the lines inside the directive should be
+	// excluded from the document.
+	skip bool
+}
+
+// parseConditional parse the line as conditional directive,
+//
+//	CONDITIONAL = ("ifdef" / "ifndef") "::" ATTR_NAMES "[" [TEXT] "]"
+//	            / "ifeval" "::[" EXPR "]"
+//	            / "endif" "::" [ATTR_NAMES] "[]"
+//
+//	ATTR_NAMES  = ATTR_NAME *( ("," / "+") ATTR_NAME )
+//
+// It will return the directive name, target, and the text inside the
+// square brackets.
+// If the line is not a valid conditional directive it will return empty
+// name.
+func parseConditional(line []byte) (name, target string, text []byte) {
+	var x = bytes.Index(line, []byte(`::`))
+	if x <= 0 {
+		return ``, ``, nil
+	}
+
+	name = string(line[:x])
+	switch name {
+	case directiveEndif, directiveIfdef, directiveIfeval, directiveIfndef:
+	default:
+		return ``, ``, nil
+	}
+
+	line = line[x+2:]
+	x = bytes.IndexByte(line, '[')
+	if x < 0 || line[len(line)-1] != ']' {
+		return ``, ``, nil
+	}
+
+	var rawTarget = line[:x]
+	if bytes.ContainsAny(rawTarget, " \t") {
+		return ``, ``, nil
+	}
+	target = string(rawTarget)
+	text = line[x+1 : len(line)-1]
+
+	switch name {
+	case directiveIfdef, directiveIfndef:
+		if len(target) == 0 {
+			return ``, ``, nil
+		}
+	case directiveIfeval:
+		if len(target) != 0 || len(bytes.TrimSpace(text)) == 0 {
+			return ``, ``, nil
+		}
+	case directiveEndif:
+		if len(text) != 0 {
+			return ``, ``, nil
+		}
+	}
+	return name, target, text
+}
+
+// isIncluded evaluate the target of "ifdef" or "ifndef".
+// The target can be single attribute name, list of names separated by ","
+// (any of them) or by "+" (all of them).
+//
+// For "ifdef" it return true if the lines inside the directive should be
+// included.
+// For "ifndef" the result is negated, following the asciidoctor rules,
+//
+//   - "ifndef::a,b[]" include the lines if none of them is defined,
+//   - "ifndef::a+b[]" include the lines if any of them is not defined.
+func (cond *conditional) isIncluded(doc *Document) bool {
+	var (
+		sep   = `,`
+		names []string
+		name  string
+		ok    bool
+	)
+
+	if strings.IndexByte(cond.target, '+') > 0 {
+		sep = `+`
+	}
+	names = strings.Split(cond.target, sep)
+
+	var isNegate = cond.name == directiveIfndef
+
+	for _, name = range names {
+		_, ok = doc.Attributes.Entry[name]
+		if sep == `,` {
+			if ok {
+				return !isNegate
+			}
+			continue
+		}
+		if !ok {
+			return isNegate
+		}
+	}
+	if sep == `,` {
+		return isNegate
+	}
+	return !isNegate
+}
+
+// evalConditionalExpr evaluate the expression in "ifeval" directive,
+//
+//	EXPR     = VALUE WSP OPERATOR WSP VALUE
+//
+//	OPERATOR = "==" / "!=" / "<" / "<=" / ">" / ">="
+//
+//	VALUE    = NUMBER / BOOLEAN / DQUOTE STRING DQUOTE / "'" STRING "'"
+//
+// Any attribute references in the expression are substituted before the
+// expression is evaluated.
+// Numbers are compared by its value, strings are compared
+// lexicographically, and boolean can only be compared by equality.
+// Comparing values with different types always return false, except for
+// "!=".
+func evalConditionalExpr(doc *Document, expr []byte) bool {
+	var (
+		lhs, op, rhs = splitConditionalExpr(expr)
+	)
+	if len(op) == 0 {
+		return false
+	}
+
+	var (
+		lval = parseConditionalValue(doc, lhs)
+		rval = parseConditionalValue(doc, rhs)
+		cmp  int
+	)
+
+	switch lv := lval.(type) {
+	case float64:
+		var rv, ok = rval.(float64)
+		if !ok {
+			return op == `!=`
+		}
+		switch {
+		case lv < rv:
+			cmp = -1
+		case lv > rv:
+			cmp = 1
+		}
+	case bool:
+		var rv, ok = rval.(bool)
+		if !ok {
+			return op == `!=`
+		}
+		switch op {
+		case `==`:
+			return lv == rv
+		case `!=`:
+			return lv != rv
+		}
+		return false
+	case string:
+		var rv, ok = rval.(string)
+		if !ok {
+			return op == `!=`
+		}
+		cmp = strings.Compare(lv, rv)
+	}
+
+	switch op {
+	case `==`:
+		return cmp == 0
+	case `!=`:
+		return cmp != 0
+	case `<`:
+		return cmp < 0
+	case `<=`:
+		return cmp <= 0
+	case `>`:
+		return cmp > 0
+	case `>=`:
+		return cmp >= 0
+	}
+	return false
+}
+
+// splitConditionalExpr split the expression into left-hand value,
+// operator, and right-hand value.
+// The operator is searched outside of the quoted values.
+func splitConditionalExpr(expr []byte) (lhs []byte, op string, rhs []byte) {
+	var (
+		quote byte
+		x     int
+		c     byte
+	)
+
+	for x = 0; x < len(expr); x++ {
+		c = expr[x]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+			continue
+		case '=', '!', '<', '>':
+		default:
+			continue
+		}
+
+		var n = 1
+		if x+1 < len(expr) && expr[x+1] == '=' {
+			n = 2
+		}
+		op = string(expr[x : x+n])
+		switch op {
+		case `==`, `!=`, `<`, `<=`, `>`, `>=`:
+		default:
+			return nil, ``, nil
+		}
+		lhs = bytes.TrimSpace(expr[:x])
+		rhs = bytes.TrimSpace(expr[x+n:])
+		return lhs, op, rhs
+	}
+	return nil, ``, nil
+}
+
+// parseConditionalValue convert the raw value into float64, bool, or
+// string.
+func parseConditionalValue(doc *Document, raw []byte) any {
+	var (
+		isQuoted bool
+		l        = len(raw)
+	)
+
+	if l >= 2 && (raw[0] == '"' || raw[0] == '\'') && raw[l-1] == raw[0] {
+		raw = raw[1 : l-1]
+		isQuoted = true
+	}
+
+	raw = htmlSubsAttr(doc, raw)
+
+	var str = string(raw)
+	if isQuoted {
+		return str
+	}
+
+	switch str {
+	case `true`:
+		return true
+	case `false`:
+		return false
+	}
+
+	var (
+		f   float64
+		err error
+	)
+	f, err = strconv.ParseFloat(strings.TrimSpace(str), 64)
+	if err == nil {
+		return f
+	}
+	return str
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+)
+
+func TestEvalConditionalExpr(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  bool
+	}
+
+	var doc = newDocument()
+	doc.Attributes.Entry[`version`] = `2.5`
+	doc.Attributes.Entry[`name`] = `beta`
+
+	var cases = []testCase{{
+		expr: `{version} > 2`,
+		exp:  true,
+	}, {
+		expr: `{version} <= 2.5`,
+		exp:  true,
+	}, {
+		expr: `{version}<2`,
+	}, {
+		expr: `"{name}" == "beta"`,
+		exp:  true,
+	}, {
+		expr: `"{name}" < "gamma"`,
+		exp:  true,
+	}, {
+		expr: `'a >= b' == 'a >= b'`,
+		exp:  true,
+	}, {
+		expr: `true != false`,
+		exp:  true,
+	}, {
+		expr: `true > false`,
+	}, {
+		expr: `"1" != 1`,
+		exp:  true,
+	}, {
+		expr: `1 = 1`,
+	}, {
+		expr: `no operator`,
+	}}
+
+	var (
+		c   testCase
+		got bool
+	)
+	for _, c = range cases {
+		got = evalConditionalExpr(doc, []byte(c.expr))
+		test.Assert(t, c.expr, c.exp, got)
+	}
+}
+
+func TestParseConditionalDiagnostics(t *testing.T) {
+	type testCase struct {
+		desc    string
+		content string
+		exp     []Diagnostic
+	}
+
+	var cases = []testCase{{
+		desc: `unterminated`,
+		content: `Before.
+
+ifdef::a[]
+Inside a.
+ifndef::b[]
+Inside not b.
+endif::[]
+ifeval::[1 == 1]
+Inside eval.`,
+		exp: []Diagnostic{{
+			Line:    3,
+			Message: `unterminated conditional directive: ifdef::a[]`,
+		}, {
+			Line:    8,
+			Message: `unterminated conditional directive: ifeval::[]`,
+		}},
+	}, {
+		desc: `mismatched`,
+		content: `ifdef::a[]
+Inside a.
+endif::b[]
+endif::a[]`,
+		exp: []Diagnostic{{
+			Line:    3,
+			Message: `mismatched conditional directive: endif::b[], expecting endif::a[]`,
+		}},
+	}, {
+		desc: `unmatched`,
+		content: `Text.
+
+endif::a[]`,
+		exp: []Diagnostic{{
+			Line:    3,
+			Message: `unmatched conditional directive: endif::a[]`,
+		}},
+	}}
+
+	var (
+		c   testCase
+		doc *Document
+	)
+	for _, c = range cases {
+		doc = Parse([]byte(c.content))
+		test.Assert(t, c.desc, c.exp, doc.Diagnostics)
+	}
+}
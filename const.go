@@ -16,3 +16,13 @@ const (
 	passSubNormal       = passSubChar | passSubQuote | passSubAttr | passSubRepl | passSubMacro | passSubPostRepl
 	passSubVerbatim     = passSubChar
 )
+
+// _passSubOrder define the default order of passthrough substitutions.
+var _passSubOrder = []int{
+	passSubChar,
+	passSubQuote,
+	passSubAttr,
+	passSubRepl,
+	passSubMacro,
+	passSubPostRepl,
+}
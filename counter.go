@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"strconv"
+	"strings"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/ascii"
+)
+
+const (
+	attrRefCounter  = `counter`
+	attrRefCounter2 = `counter2`
+)
+
+// counterRef increment the counter in attribute reference,
+//
+//	"{" ("counter" / "counter2") ":" NAME [ ":" SEED ] "}"
+//
+// The counter value is stored in document attribute NAME, so it can be
+// referenced later using "{NAME}".
+// The SEED is the first value of counter, either number or single letter,
+// default to 1.
+// The "counter" return the new value while "counter2" return empty string.
+// It will return false if the ref is not counter.
+func (doc *Document) counterRef(ref string) (val string, ok bool) {
+	var (
+		fields = strings.SplitN(ref, `:`, 3)
+		kind   = strings.ToLower(strings.TrimSpace(fields[0]))
+	)
+	if len(fields) < 2 {
+		return ``, false
+	}
+	if kind != attrRefCounter && kind != attrRefCounter2 {
+		return ``, false
+	}
+
+	var name = strings.ToLower(strings.TrimSpace(fields[1]))
+	if len(name) == 0 {
+		return ``, false
+	}
+
+	var seed string
+	if len(fields) == 3 {
+		seed = strings.TrimSpace(fields[2])
+	}
+
+	val = counterNext(doc.Attributes.Entry[name], seed)
+	doc.Attributes.Entry[name] = val
+
+	if kind == attrRefCounter2 {
+		return ``, true
+	}
+	return val, true
+}
+
+// counterNext return the next value of counter.
+// If the val is empty, the counter is not started yet and it will return
+// the seed, or "1" if the seed is empty.
+// If the val is a number it will return val+1.
+// If the val contains only letters it will return the next letters, for
+// example "b" for "a", "aa" for "z", and "ba" for "az".
+// Other values are returned as is.
+func counterNext(val, seed string) string {
+	if len(val) == 0 {
+		if len(seed) == 0 {
+			return `1`
+		}
+		return seed
+	}
+
+	var n, err = strconv.Atoi(val)
+	if err == nil {
+		return strconv.Itoa(n + 1)
+	}
+
+	var (
+		next = []byte(val)
+		x    int
+		c    byte
+	)
+	for x = range next {
+		if !ascii.IsAlpha(next[x]) {
+			return val
+		}
+	}
+	for x = len(next) - 1; x >= 0; x-- {
+		c = next[x]
+		switch c {
+		case 'z':
+			next[x] = 'a'
+		case 'Z':
+			next[x] = 'A'
+		default:
+			next[x] = c + 1
+			return string(next)
+		}
+	}
+	// All letters are wrapped, prepend the first letter.
+	return string(next[0]) + string(next)
+}
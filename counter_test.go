@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+)
+
+func TestCounterNext(t *testing.T) {
+	type testCase struct {
+		desc string
+		val  string
+		seed string
+		exp  string
+	}
+
+	var cases = []testCase{{
+		desc: `empty`,
+		exp:  `1`,
+	}, {
+		desc: `empty with seed`,
+		seed: `5`,
+		exp:  `5`,
+	}, {
+		desc: `empty with letter seed`,
+		seed: `A`,
+		exp:  `A`,
+	}, {
+		desc: `number`,
+		val:  `1`,
+		seed: `5`,
+		exp:  `2`,
+	}, {
+		desc: `number with leading zero`,
+		val:  `09`,
+		exp:  `10`,
+	}, {
+		desc: `letter`,
+		val:  `a`,
+		exp:  `b`,
+	}, {
+		desc: `letter z`,
+		val:  `z`,
+		exp:  `aa`,
+	}, {
+		desc: `letter Z`,
+		val:  `Z`,
+		exp:  `AA`,
+	}, {
+		desc: `letters`,
+		val:  `aa`,
+		exp:  `ab`,
+	}, {
+		desc: `letters with carry`,
+		val:  `az`,
+		exp:  `ba`,
+	}, {
+		desc: `letters with carry upper case`,
+		val:  `AZ`,
+		exp:  `BA`,
+	}, {
+		desc: `letters wrapped`,
+		val:  `zz`,
+		exp:  `aaa`,
+	}, {
+		desc: `mixed`,
+		val:  `x1`,
+		exp:  `x1`,
+	}}
+
+	var c testCase
+	for _, c = range cases {
+		test.Assert(t, c.desc, c.exp, counterNext(c.val, c.seed))
+	}
+}
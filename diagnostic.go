@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Diagnostic contains the problem found while parsing the document that
+// does not stop the parser, for example an unclosed tag in included file.
+type Diagnostic struct {
+	// File is the path of file where the problem found.
+	// It may be empty if the Document is created using [Parse].
+	File string
+
+	Message string
+
+	// Line is the line number in the File, start from 1.
+	// It is zero if the line number is not known.
+	Line int
+}
+
+// String return the diagnostic in the format "FILE:LINE: MESSAGE".
+// The FILE and LINE are omitted if its empty.
+func (diag Diagnostic) String() string {
+	var sb strings.Builder
+
+	if len(diag.File) != 0 {
+		sb.WriteString(diag.File)
+		if diag.Line > 0 {
+			fmt.Fprintf(&sb, `:%d`, diag.Line)
+		}
+		sb.WriteString(`: `)
+	} else if diag.Line > 0 {
+		fmt.Fprintf(&sb, `line %d: `, diag.Line)
+	}
+	sb.WriteString(diag.Message)
+	return sb.String()
+}
+
+// addDiagnostic append new Diagnostic into the Document.
+func (doc *Document) addDiagnostic(file string, line int, format string, args ...any) {
+	var diag = Diagnostic{
+		File:    file,
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	}
+	doc.Diagnostics = append(doc.Diagnostics, diag)
+}
+
+// lineSource return the path of file and the line number in the file for
+// lineNum, the line number in the document content after include
+// directives expanded.
+// It return zero line number if lineNum is zero.
+func (doc *Document) lineSource(lineNum int) (file string, n int) {
+	if lineNum <= 0 {
+		return doc.file, 0
+	}
+	if doc.docp == nil {
+		return doc.file, lineNum
+	}
+	return doc.docp.lineSource(lineNum - 1)
+}
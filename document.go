@@ -6,6 +6,7 @@ package asciidoctor
 import (
 	"bytes"
 	"fmt"
+	"html"
 	"io"
 	"os"
 	"path/filepath"
@@ -17,9 +18,12 @@ import (
 const (
 	defSectnumlevels  = 3
 	defTOCLevel       = 2
-	defTOCTitle       = `Table of Contents`
 	defTitleSeparator = ':'
-	defVersionPrefix  = `version `
+)
+
+const (
+	timeFormatDate = `2006-01-02`
+	timeFormatTime = `15:04:05 Z0700`
 )
 
 // Document represent content of asciidoc that has been parsed.
@@ -34,13 +38,37 @@ type Document struct {
 	Attributes DocumentAttribute
 	sectnums   *sectionCounters
 
+	// docp is the parser of document content, used to get the file and
+	// line number of line in the content after include directives
+	// expanded.
+	docp *documentParser
+
 	// titleID is the reverse of anchors, it contains mapping of title and
 	// its ID.
 	titleID map[string]string
 
+	// userLabels contains the keys of label attributes that has been set
+	// in the document, which are not replaced by setLang.
+	userLabels map[string]struct{}
+
 	// List of footnote ID and its text.
 	footnotes []*macro
 
+	// bibRefs contains the ID of bibliography entries.
+	bibRefs []reference
+
+	// xrefs contains the target of cross references in the order of
+	// their occurrence in the document.
+	xrefs []reference
+
+	// indexTerms contains list of index terms in the order of their
+	// occurrence in the document.
+	indexTerms []*indexTerm
+
+	callout calloutState
+
+	includeResolver IncludeResolver
+
 	Revision Revision
 
 	file string
@@ -53,7 +81,6 @@ type Document struct {
 	rawAuthors  string
 	rawRevision string
 	tocPosition string
-	tocTitle    string
 
 	Title DocumentTitle
 
@@ -62,11 +89,17 @@ type Document struct {
 
 	Authors []*Author
 
-	TOCLevel       int
-	sectLevel      int
-	counterExample int
-	counterImage   int
-	counterTable   int
+	// Diagnostics contains list of problems found while parsing the
+	// document, for example unclosed tag in included file.
+	Diagnostics []Diagnostic
+
+	TOCLevel        int
+	sectLevel       int
+	counterAppendix int
+	counterExample  int
+	counterImage    int
+	counterPart     int
+	counterTable    int
 
 	isEmbedded   bool
 	isForToC     bool
@@ -80,11 +113,11 @@ func newDocument() (doc *Document) {
 		},
 		TOCLevel:   defTOCLevel,
 		tocClasses: attributeClass{},
-		tocTitle:   defTOCTitle,
 		Attributes: newDocumentAttribute(),
 		classes:    attributeClass{},
 		anchors:    make(map[string]*anchor),
 		titleID:    make(map[string]string),
+		userLabels: make(map[string]struct{}),
 		sectnums:   &sectionCounters{},
 		sectLevel:  defSectnumlevels,
 		header: &element{
@@ -99,6 +132,11 @@ func newDocument() (doc *Document) {
 
 // Open the ascidoc file and parse it.
 func Open(file string) (doc *Document, err error) {
+	return OpenWithOptions(file, Options{})
+}
+
+// OpenWithOptions open the ascidoc file and parse it using custom options.
+func OpenWithOptions(file string, opts Options) (doc *Document, err error) {
 	var (
 		fi  os.FileInfo
 		raw []byte
@@ -115,12 +153,21 @@ func Open(file string) (doc *Document, err error) {
 	}
 
 	doc = newDocument()
+	doc.setOptions(opts)
 	doc.file = file
 	doc.docdir = filepath.Dir(file)
 
-	var modTime = fi.ModTime().Round(time.Second).Format(`2006-01-02 15:04:05 Z0700`)
-	doc.Attributes.Entry[docAttrLastUpdateValue] = modTime
+	var (
+		suffix = filepath.Ext(file)
+		name   = strings.TrimSuffix(filepath.Base(file), suffix)
+	)
 	doc.Attributes.Entry[docAttrDocdir] = doc.docdir
+	doc.Attributes.Entry[docAttrDocFile] = file
+	doc.Attributes.Entry[docAttrDocFileSuffix] = suffix
+	doc.Attributes.Entry[docAttrDocName] = name
+
+	doc.setDate(opts, fi.ModTime())
+	doc.Attributes.Entry[docAttrLastUpdateValue] = doc.Attributes.Entry[docAttrDocDatetime]
 
 	parse(doc, raw)
 
@@ -129,11 +176,29 @@ func Open(file string) (doc *Document, err error) {
 
 // Parse the content into a Document.
 func Parse(content []byte) (doc *Document) {
+	return ParseWithOptions(content, Options{})
+}
+
+// ParseWithOptions parse the content into a Document using custom options.
+func ParseWithOptions(content []byte, opts Options) (doc *Document) {
 	doc = newDocument()
+	doc.setOptions(opts)
+
+	// Without file, the document date is the local date.
+	doc.setDate(opts, time.Time{})
+
 	parse(doc, content)
 	return doc
 }
 
+// setOptions set the document fields and attributes from opts.
+func (doc *Document) setOptions(opts Options) {
+	doc.includeResolver = opts.IncludeResolver
+	if opts.AllowURIRead {
+		doc.Attributes.Entry[docAttrAllowURIRead] = ``
+	}
+}
+
 func parse(doc *Document, content []byte) {
 	var (
 		docp = newDocumentParser(doc, content)
@@ -160,6 +225,12 @@ func parse(doc *Document, content []byte) {
 		docp.parseBlock(doc.preamble, 0)
 	}
 	docp.parseBlock(doc.content, 0)
+	if doc.isBook() {
+		doc.postParseParts()
+	}
+	docp.checkConditionals()
+	doc.checkCallouts()
+	doc.checkReferences()
 }
 
 // ToHTMLEmbedded convert the Document object into HTML with content only,
@@ -186,7 +257,7 @@ func (doc *Document) ToHTML(out io.Writer) (err error) {
 	// Use *bytes.Buffer to minimize checking for error.
 	var buf = &bytes.Buffer{}
 
-	fmt.Fprint(buf, _htmlBegin)
+	fmt.Fprintf(buf, _htmlBegin, doc.htmlAttrLangDir())
 
 	docAttrValue = doc.Attributes.Entry[DocAttrGenerator]
 	if len(docAttrValue) > 0 {
@@ -221,6 +292,11 @@ func (doc *Document) ToHTML(out io.Writer) (err error) {
 		buf.WriteString(_defaultCSS)
 	}
 
+	if doc.Attributes.Entry[docAttrSourceHighlighter] == sourceHighlighterBuiltin {
+		buf.WriteByte('\n')
+		buf.WriteString(_highlightBuiltinCSS)
+	}
+
 	fmt.Fprintf(buf, "\n</head>\n<body class=%q>\n", doc.classes.String())
 
 	var isWithHeaderFooter = true
@@ -251,7 +327,11 @@ func (doc *Document) ToHTMLBody(out io.Writer) (err error) {
 }
 
 func (doc *Document) generateClasses() {
-	doc.classes.add(classNameArticle)
+	if doc.isBook() {
+		doc.classes.add(classNameBook)
+	} else {
+		doc.classes.add(classNameArticle)
+	}
 	doc.tocPosition, doc.tocIsEnabled = doc.Attributes.Entry[docAttrTOC]
 
 	switch doc.tocPosition {
@@ -284,8 +364,31 @@ func (doc *Document) haveHeader() bool {
 	return false
 }
 
+// htmlAttrLangDir return the "lang" and "dir" attributes for the HTML root
+// element from the document attributes with the same name.
+// The "dir" is ignored if its not "ltr", "rtl", or "auto".
+func (doc *Document) htmlAttrLangDir() string {
+	var (
+		sb strings.Builder
+		v  = doc.Attributes.Entry[docAttrLang]
+	)
+	if len(v) > 0 {
+		fmt.Fprintf(&sb, ` lang="%s"`, html.EscapeString(v))
+	}
+	v = doc.Attributes.Entry[docAttrDir]
+	switch v {
+	case docAttrValueAuto, docAttrValueLTR, docAttrValueRTL:
+		fmt.Fprintf(&sb, ` dir="%s"`, v)
+	}
+	return sb.String()
+}
+
 // setAttribute store the document attribute val by its key.
 func (doc *Document) setAttribute(key, val string) (err error) {
+	if strings.Trim(key, `! `) == docAttrAllowURIRead {
+		// The "allow-uri-read" can only be set from Options.
+		return nil
+	}
 	if key[0] == '!' {
 		key = strings.TrimSpace(key[1:])
 		delete(doc.Attributes.Entry, key)
@@ -314,6 +417,10 @@ func (doc *Document) setAttribute(key, val string) (err error) {
 		}
 		doc.Attributes.Entry[key] = val
 
+	case docAttrLang:
+		doc.Attributes.Entry[key] = val
+		doc.setLang(val)
+
 	case docAttrDocdir:
 		if val == `` {
 			doc.Attributes.Entry[key] = doc.docdir
@@ -323,6 +430,10 @@ func (doc *Document) setAttribute(key, val string) (err error) {
 
 	default:
 		doc.Attributes.Entry[key] = val
+		var _, isLabel = _labels[defLang][key]
+		if isLabel {
+			doc.userLabels[key] = struct{}{}
+		}
 	}
 
 	return nil
@@ -407,6 +518,39 @@ func (doc *Document) registerFootnote(id string, rawContent []byte) (mcr *macro,
 	return mcr, false
 }
 
+// setDate set the date and time attributes using the time from options.
+// The modTime is the modification time of document file, its ignored if
+// its zero or the options has fixed time.
+func (doc *Document) setDate(opts Options, modTime time.Time) {
+	var now, isFixed, err = opts.now()
+	if err != nil {
+		doc.addDiagnostic(doc.file, 0, `%s`, err)
+	}
+	if isFixed || modTime.IsZero() {
+		modTime = now
+	}
+	doc.setDocDate(modTime)
+	doc.setLocalDate(now)
+}
+
+// setDocDate set the intrinsic attributes "docdate", "doctime", and
+// "docdatetime" from the modification time of document.
+func (doc *Document) setDocDate(t time.Time) {
+	t = t.Round(time.Second)
+	doc.Attributes.Entry[docAttrDocDate] = t.Format(timeFormatDate)
+	doc.Attributes.Entry[docAttrDocTime] = t.Format(timeFormatTime)
+	doc.Attributes.Entry[docAttrDocDatetime] = t.Format(timeFormatDate + ` ` + timeFormatTime)
+}
+
+// setLocalDate set the intrinsic attributes "localdate", "localtime", and
+// "localdatetime" from the time when the document is converted.
+func (doc *Document) setLocalDate(t time.Time) {
+	t = t.Round(time.Second)
+	doc.Attributes.Entry[docAttrLocalDate] = t.Format(timeFormatDate)
+	doc.Attributes.Entry[docAttrLocalTime] = t.Format(timeFormatTime)
+	doc.Attributes.Entry[docAttrLocalDatetime] = t.Format(timeFormatDate + ` ` + timeFormatTime)
+}
+
 // tocHTML write table of contents with HTML template into out.
 func (doc *Document) tocHTML(out io.Writer) {
 	var (
@@ -422,13 +566,17 @@ func (doc *Document) tocHTML(out io.Writer) {
 		}
 	}
 
-	v, ok = doc.Attributes.Entry[docAttrTOCTitle]
-	if ok && len(v) > 0 {
-		doc.tocTitle = v
+	v, ok = doc.label(docAttrTOCTitle)
+	if !ok {
+		v = doc.labelDefault(docAttrTOCTitle)
 	}
 
-	fmt.Fprintf(out, _htmlToCBegin, doc.tocClasses.String(), doc.tocTitle)
-	htmlWriteToC(doc, doc.content, out, 0)
+	fmt.Fprintf(out, _htmlToCBegin, doc.tocClasses.String(), v)
+	if doc.isBook() {
+		htmlWriteToCBook(doc, out)
+	} else {
+		htmlWriteToC(doc, doc.content, out, 0)
+	}
 	fmt.Fprint(out, "\n</div>")
 }
 
@@ -18,47 +18,85 @@ const (
 	// ":stylesheet: my.css".
 	DocAttrStylesheet = `stylesheet`
 
-	docAttrAuthorInitials  = `authorinitials`
-	docAttrDocdir          = `docdir`
-	docAttrDocTitle        = `doctitle`
-	docAttrEmail           = attrValueEmail
-	docAttrFirstName       = `firstname`
-	docAttrIDPrefix        = `idprefix`
-	docAttrIDSeparator     = `idseparator`
-	docAttrLastName        = `lastname`
-	docAttrLastUpdateLabel = `last-update-label`
-	docAttrLastUpdateValue = `last-update-value`
-	docAttrLevelOffset     = `leveloffset`
-	docAttrMiddleName      = `middlename`
-	docAttrNoFooter        = `nofooter`
-	docAttrNoHeader        = `noheader`
-	docAttrNoHeaderFooter  = `no-header-footer`
-	docAttrNoTitle         = `notitle`
-	docAttrRevDate         = `revdate`
-	docAttrRevNumber       = `revnumber`
-	docAttrRevRemark       = `revremark`
-	docAttrSectAnchors     = `sectanchors`
-	docAttrSectIDs         = `sectids`
-	docAttrSectLinks       = `sectlinks`
-	docAttrSectNumLevel    = `sectnumlevels`
-	docAttrSectNums        = `sectnums`
-	docAttrShowTitle       = `showtitle`
-	docAttrTOC             = `toc`
-	docAttrTOCLevels       = `toclevels`
-	docAttrTOCTitle        = `toc-title`
-	docAttrTableCaption    = `table-caption`
-	docAttrTitle           = attrNameTitle
-	docAttrTitleSeparator  = `title-separator`
-	docAttrVersionLabel    = `version-label`
+	docAttrAllowURIRead         = `allow-uri-read`
+	docAttrAppendixCaption      = `appendix-caption`
+	docAttrAsciidoctorVersion   = `asciidoctor-version`
+	docAttrAttributeMissing     = `attribute-missing`
+	docAttrAttributeUndefined   = `attribute-undefined`
+	docAttrAuthorInitials       = `authorinitials`
+	docAttrBackend              = `backend`
+	docAttrCautionCaption       = `caution-caption`
+	docAttrChapterSignifier     = `chapter-signifier`
+	docAttrDir                  = `dir`
+	docAttrDocDate              = `docdate`
+	docAttrDocDatetime          = `docdatetime`
+	docAttrDocdir               = `docdir`
+	docAttrDocFile              = `docfile`
+	docAttrDocFileSuffix        = `docfilesuffix`
+	docAttrDocName              = `docname`
+	docAttrDocTime              = `doctime`
+	docAttrDocTitle             = `doctitle`
+	docAttrDocType              = `doctype`
+	docAttrEmail                = attrValueEmail
+	docAttrExampleCaption       = `example-caption`
+	docAttrExperimental         = `experimental`
+	docAttrFigureCaption        = `figure-caption`
+	docAttrFirstName            = `firstname`
+	docAttrHardbreaks           = `hardbreaks`
+	docAttrHardbreaksOption     = `hardbreaks-option`
+	docAttrIDPrefix             = `idprefix`
+	docAttrIDSeparator          = `idseparator`
+	docAttrImportantCaption     = `important-caption`
+	docAttrLang                 = `lang`
+	docAttrLastName             = `lastname`
+	docAttrLastUpdateLabel      = `last-update-label`
+	docAttrLastUpdateValue      = `last-update-value`
+	docAttrLevelOffset          = `leveloffset`
+	docAttrLocalDate            = `localdate`
+	docAttrLocalDatetime        = `localdatetime`
+	docAttrLocalTime            = `localtime`
+	docAttrMiddleName           = `middlename`
+	docAttrNoFooter             = `nofooter`
+	docAttrNoHeader             = `noheader`
+	docAttrNoHeaderFooter       = `no-header-footer`
+	docAttrNoteCaption          = `note-caption`
+	docAttrNoTitle              = `notitle`
+	docAttrOutFileSuffix        = `outfilesuffix`
+	docAttrPartNums             = `partnums`
+	docAttrPartSignifier        = `part-signifier`
+	docAttrRevDate              = `revdate`
+	docAttrRevNumber            = `revnumber`
+	docAttrRevRemark            = `revremark`
+	docAttrSectAnchors          = `sectanchors`
+	docAttrSectIDs              = `sectids`
+	docAttrSectLinks            = `sectlinks`
+	docAttrSectNumLevel         = `sectnumlevels`
+	docAttrSectNums             = `sectnums`
+	docAttrShowTitle            = `showtitle`
+	docAttrSourceHighlighter    = `source-highlighter`
+	docAttrSourceLinenumsOption = `source-linenums-option`
+	docAttrStem                 = `stem`
+	docAttrTipCaption           = `tip-caption`
+	docAttrTOC                  = `toc`
+	docAttrTOCLevels            = `toclevels`
+	docAttrTOCTitle             = `toc-title`
+	docAttrTableCaption         = `table-caption`
+	docAttrTitle                = attrNameTitle
+	docAttrTitleSeparator       = `title-separator`
+	docAttrVersionLabel         = `version-label`
+	docAttrWarningCaption       = `warning-caption`
 )
 
 // List of possible document attribute value.
 const (
 	docAttrValueAuto     = `auto`
+	docAttrValueBook     = `book`
 	docAttrValueMacro    = `macro`
 	docAttrValuePreamble = `preamble`
 	docAttrValueLeft     = `left`
+	docAttrValueLTR      = `ltr`
 	docAttrValueRight    = `right`
+	docAttrValueRTL      = `rtl`
 )
 
 // DocumentAttribute contains the mapping of global attribute keys in the
@@ -69,16 +107,25 @@ type DocumentAttribute struct {
 }
 
 func newDocumentAttribute() DocumentAttribute {
-	return DocumentAttribute{
+	var docAttr = DocumentAttribute{
 		Entry: map[string]string{
-			DocAttrGenerator:       `asciidoctor-go ` + Version,
-			docAttrLastUpdateLabel: `Last updated`,
-			docAttrLastUpdateValue: ``,
-			docAttrSectIDs:         ``,
-			docAttrShowTitle:       ``,
-			DocAttrStylesheet:      ``, // Default to embedded CSS.
-			docAttrTableCaption:    ``,
-			docAttrVersionLabel:    ``,
+			DocAttrGenerator:          `asciidoctor-go ` + Version,
+			docAttrAsciidoctorVersion: Version,
+			docAttrAttributeMissing:   attrValueSkip,
+			docAttrAttributeUndefined: attrValueDropLine,
+			docAttrBackend:            `html5`,
+			docAttrLang:               defLang,
+			docAttrLastUpdateValue:    ``,
+			docAttrOutFileSuffix:      `.html`,
+			docAttrSectIDs:            ``,
+			docAttrShowTitle:          ``,
+			DocAttrStylesheet:         ``, // Default to embedded CSS.
 		},
 	}
+
+	var key, val string
+	for key, val = range _labels[defLang] {
+		docAttr.Entry[key] = val
+	}
+	return docAttr
 }
@@ -14,17 +14,43 @@ import (
 const debugLevel = 0
 
 type documentParser struct {
-	doc      *Document
-	lines    [][]byte
+	doc   *Document
+	lines [][]byte
+
+	// conds contains stack of conditional directives that has not been
+	// closed yet.
+	conds []*conditional
+
+	// lineOffsets contains the section level offset for each line in
+	// lines, set by "include" directive with "leveloffset" attribute.
+	// It is nil if none of the include directive set the leveloffset.
+	lineOffsets []int
+
+	// lineFiles contains the path of included file for each line in
+	// lines, set by "include" directive.
+	// The empty path means the line is from the document itself.
+	// It is nil if none of the include directive has been expanded.
+	lineFiles []string
+
+	// lineNums contains the line number in its file for each line in
+	// lines, set by "include" directive.
+	// It is nil if none of the include directive has been expanded.
+	lineNums []int
+
 	lineNum  int
 	prevKind int
 	kind     int
+
+	// levelOffset is the section level offset of the current line
+	// from lineOffsets.
+	levelOffset int
 }
 
 func newDocumentParser(doc *Document, content []byte) (docp *documentParser) {
 	docp = &documentParser{
 		doc: doc,
 	}
+	doc.docp = docp
 
 	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
 	docp.lines = bytes.Split(content, []byte("\n"))
@@ -50,6 +76,7 @@ func parseSub(parentDoc *Document, content []byte) (subdoc *Document) {
 	)
 
 	subdoc = newDocument()
+	subdoc.includeResolver = parentDoc.includeResolver
 
 	for k, v = range parentDoc.Attributes.Entry {
 		subdoc.Attributes.Entry[k] = v
@@ -79,6 +106,11 @@ func (docp *documentParser) consumeLinesUntil(el *element, term int, terms []int
 		term == elKindLiteralParagraph {
 		allowComment = true
 	}
+	if el.lineNum == 0 {
+		// The content may already contains the first line that
+		// has been read by the caller.
+		el.lineNum = docp.lineNum - bytes.Count(el.raw, []byte{'\n'}) + 1
+	}
 	for {
 		spaces, line, ok = docp.line(logp)
 		if !ok {
@@ -105,7 +137,7 @@ func (docp *documentParser) consumeLinesUntil(el *element, term int, terms []int
 			}
 		}
 		if docp.kind == lineKindInclude {
-			elInclude = parseInclude(docp.doc, line)
+			elInclude = docp.parseIncludeLine(line)
 			if elInclude == nil {
 				el.Write(line)
 				el.WriteByte('\n')
@@ -147,8 +179,13 @@ func (docp *documentParser) hasPreamble() bool {
 		if len(line) == 0 {
 			continue
 		}
+		if docp.isLineBlockAttribute(start) {
+			continue
+		}
+		docp.levelOffset = docp.lineOffset(start)
 		_, _ = docp.whatKindOfLine(line)
-		if docp.kind == elKindSectionL1 ||
+		if docp.kind == elKindSectionL0 ||
+			docp.kind == elKindSectionL1 ||
 			docp.kind == elKindSectionL2 ||
 			docp.kind == elKindSectionL3 ||
 			docp.kind == elKindSectionL4 ||
@@ -162,20 +199,61 @@ func (docp *documentParser) hasPreamble() bool {
 	return false
 }
 
+// isLineBlockAttribute return true if the line at index x is block
+// attributes, block style, or block title.
+func (docp *documentParser) isLineBlockAttribute(x int) bool {
+	var kind = docp.kind
+
+	docp.levelOffset = docp.lineOffset(x)
+	_, _ = docp.whatKindOfLine(docp.lines[x])
+	kind, docp.kind = docp.kind, kind
+
+	switch kind {
+	case lineKindAttributeElement, lineKindStyleClass, lineKindBlockTitle:
+		return true
+	}
+	return false
+}
+
 func (docp *documentParser) include(el *elementInclude) {
 	var content = bytes.ReplaceAll(el.content, []byte("\r\n"), []byte("\n"))
 
 	content = bytes.TrimRight(content, "\n")
 
 	var (
-		includedLines = bytes.Split(content, []byte("\n"))
-		newLines      = make([][]byte, 0, len(docp.lines)+len(includedLines))
-
-		line []byte
+		includedLines [][]byte
+		newLines      [][]byte
+		line          []byte
 	)
+	if len(content) != 0 {
+		includedLines = bytes.Split(content, []byte("\n"))
+	}
+	newLines = make([][]byte, 0, len(docp.lines)+len(includedLines))
 
 	// Do not add the "include" directive
 	docp.lineNum--
+
+	var (
+		offset         = docp.lineOffset(docp.lineNum)
+		includedOffset = offset
+
+		v          int
+		isRelative bool
+		ok         bool
+	)
+	v, isRelative, ok = el.levelOffset()
+	if ok {
+		if isRelative {
+			includedOffset = offset + v
+		} else {
+			includedOffset = v - docp.doc.Attributes.LevelOffset
+		}
+	}
+	if docp.lineOffsets != nil || includedOffset != offset {
+		docp.includeLineOffsets(len(includedLines), includedOffset)
+	}
+	docp.includeLineSources(el, len(includedLines))
+
 	newLines = append(newLines, docp.lines[:docp.lineNum]...)
 	newLines = append(newLines, includedLines...)
 	newLines = append(newLines, docp.lines[docp.lineNum+1:]...)
@@ -188,32 +266,239 @@ func (docp *documentParser) include(el *elementInclude) {
 	}
 }
 
+// includeLineOffsets replace the level offset of current include directive
+// line with n lines of offset.
+func (docp *documentParser) includeLineOffsets(n, offset int) {
+	if docp.lineOffsets == nil {
+		docp.lineOffsets = make([]int, len(docp.lines))
+	}
+
+	var (
+		newOffsets = make([]int, 0, len(docp.lineOffsets)+n)
+		x          int
+	)
+
+	newOffsets = append(newOffsets, docp.lineOffsets[:docp.lineNum]...)
+	for x = 0; x < n; x++ {
+		newOffsets = append(newOffsets, offset)
+	}
+	newOffsets = append(newOffsets, docp.lineOffsets[docp.lineNum+1:]...)
+	docp.lineOffsets = newOffsets
+}
+
+// includeLineSources replace the file and line number of current include
+// directive line with the n lines from the included file.
+// If the content of el is a placeholder, the lines keep the file and line
+// number of the directive.
+func (docp *documentParser) includeLineSources(el *elementInclude, n int) {
+	var x int
+
+	if docp.lineFiles == nil {
+		docp.lineFiles = make([]string, len(docp.lines))
+		docp.lineNums = make([]int, len(docp.lines))
+		for x = range docp.lineNums {
+			docp.lineNums[x] = x + 1
+		}
+	}
+
+	var (
+		newFiles = make([]string, 0, len(docp.lineFiles)+n)
+		newNums  = make([]int, 0, len(docp.lineNums)+n)
+		file     = el.fpath
+		num      int
+	)
+
+	newFiles = append(newFiles, docp.lineFiles[:docp.lineNum]...)
+	newNums = append(newNums, docp.lineNums[:docp.lineNum]...)
+	for x = 0; x < n; x++ {
+		switch {
+		case el.isPlaceholder:
+			file = el.from
+			num = el.lineNum
+		case x < len(el.lineNums):
+			num = el.lineNums[x]
+		default:
+			num = x + 1
+		}
+		newFiles = append(newFiles, file)
+		newNums = append(newNums, num)
+	}
+	newFiles = append(newFiles, docp.lineFiles[docp.lineNum+1:]...)
+	newNums = append(newNums, docp.lineNums[docp.lineNum+1:]...)
+	docp.lineFiles = newFiles
+	docp.lineNums = newNums
+}
+
+// lineSource return the path of file and the line number in the file
+// where the line at index x come from.
+func (docp *documentParser) lineSource(x int) (file string, lineNum int) {
+	if x < 0 || x >= len(docp.lineNums) {
+		return docp.doc.file, x + 1
+	}
+	file = docp.lineFiles[x]
+	if len(file) == 0 {
+		file = docp.doc.file
+	}
+	return file, docp.lineNums[x]
+}
+
+// parseIncludeLine parse the include directive in the current line.
+func (docp *documentParser) parseIncludeLine(line []byte) *elementInclude {
+	var from, lineNum = docp.lineSource(docp.lineNum - 1)
+	return parseInclude(docp.doc, line, from, lineNum)
+}
+
+// lineOffset return the section level offset of line at index x.
+func (docp *documentParser) lineOffset(x int) int {
+	if x < 0 || x >= len(docp.lineOffsets) {
+		return 0
+	}
+	return docp.lineOffsets[x]
+}
+
 // line return the next line in the content of raw document.
 // It will return ok as false if there are no more line.
 func (docp *documentParser) line(logp string) (spaces, line []byte, ok bool) {
 	docp.prevKind = docp.kind
 
-	if docp.lineNum >= len(docp.lines) {
-		return nil, nil, false
-	}
+	for {
+		if docp.lineNum >= len(docp.lines) {
+			return nil, nil, false
+		}
 
-	line = docp.lines[docp.lineNum]
-	if debugLevel >= 2 {
-		fmt.Printf("line %3d: %s: %s\n", docp.lineNum, logp, line)
+		line = docp.lines[docp.lineNum]
+		if debugLevel >= 2 {
+			fmt.Printf("line %3d: %s: %s\n", docp.lineNum, logp, line)
+		}
+		docp.lineNum++
+
+		line, ok = docp.preprocess(line)
+		if ok {
+			break
+		}
 	}
-	docp.lineNum++
 
+	docp.levelOffset = docp.lineOffset(docp.lineNum - 1)
 	spaces, line = docp.whatKindOfLine(line)
 	return spaces, line, true
 }
 
+// preprocess handle the conditional directives "ifdef", "ifndef", "ifeval",
+// and "endif".
+// It will return ok as false if the line is a directive or the line is
+// excluded by the previous directive.
+// If the line is a single line "ifdef" or "ifndef" with text inside the
+// square brackets, the text will be returned as line.
+func (docp *documentParser) preprocess(line []byte) (got []byte, ok bool) {
+	var isSkip = len(docp.conds) > 0 && docp.conds[len(docp.conds)-1].skip
+
+	if len(line) < len(directiveIfdef) {
+		return line, !isSkip
+	}
+	if line[0] == '\\' {
+		var name, _, _ = parseConditional(line[1:])
+		if len(name) != 0 {
+			// The directive is escaped.
+			return line[1:], !isSkip
+		}
+		return line, !isSkip
+	}
+
+	var name, target, text = parseConditional(line)
+
+	switch name {
+	case ``:
+		return line, !isSkip
+
+	case directiveEndif:
+		docp.endConditional(line, target)
+		return nil, false
+	}
+
+	var cond = &conditional{
+		name:   name,
+		target: target,
+	}
+	cond.file, cond.lineNum = docp.lineSource(docp.lineNum - 1)
+
+	if isSkip {
+		// Keep track of nested directive inside excluded lines, so
+		// we can match their "endif".
+		cond.skip = true
+		if name == directiveIfeval || len(text) == 0 {
+			docp.conds = append(docp.conds, cond)
+		}
+		return nil, false
+	}
+
+	switch name {
+	case directiveIfeval:
+		cond.skip = !evalConditionalExpr(docp.doc, text)
+		docp.conds = append(docp.conds, cond)
+		return nil, false
+	}
+
+	cond.skip = !cond.isIncluded(docp.doc)
+	if len(text) != 0 {
+		// Single line directive, "ifdef::name[TEXT]".
+		if cond.skip {
+			return nil, false
+		}
+		return text, true
+	}
+	docp.conds = append(docp.conds, cond)
+	return nil, false
+}
+
+// endConditional close the last conditional directive by "endif" in line.
+// The endif with target that does not match with the last directive, or
+// without any open directive, is reported as Diagnostic and ignored.
+func (docp *documentParser) endConditional(line []byte, target string) {
+	var (
+		file, lineNum = docp.lineSource(docp.lineNum - 1)
+		lastIdx       = len(docp.conds) - 1
+	)
+	if lastIdx < 0 {
+		docp.doc.addDiagnostic(file, lineNum,
+			`unmatched conditional directive: %s`, line)
+		return
+	}
+	var cond = docp.conds[lastIdx]
+	if len(target) != 0 && target != cond.target {
+		docp.doc.addDiagnostic(file, lineNum,
+			`mismatched conditional directive: %s, expecting %s::%s[]`,
+			line, directiveEndif, cond.target)
+		return
+	}
+	docp.conds = docp.conds[:lastIdx]
+}
+
+// checkConditionals report the conditional directives that are not closed
+// by "endif" at the end of document.
+func (docp *documentParser) checkConditionals() {
+	var cond *conditional
+	for _, cond = range docp.conds {
+		docp.doc.addDiagnostic(cond.file, cond.lineNum,
+			`unterminated conditional directive: %s::%s[]`,
+			cond.name, cond.target)
+	}
+	docp.conds = nil
+}
+
 // parseAttribute parse document attribute and return its key and optional
 // value.
+//
+// The key is empty if the attribute entry is dropped because its value
+// contains reference to missing attribute and "attribute-missing" is
+// "drop-line".
 func (docp *documentParser) parseAttribute(line []byte, strict bool) (key, value string, ok bool) {
 	var (
-		bb bytes.Buffer
-		p  int
-		x  int
+		file, lineNum = docp.lineSource(docp.lineNum - 1)
+
+		bb        bytes.Buffer
+		p         int
+		x         int
+		isDropped bool
 	)
 
 	if !(ascii.IsAlnum(line[1]) || line[1] == '_') {
@@ -251,6 +536,11 @@ func (docp *documentParser) parseAttribute(line []byte, strict bool) (key, value
 		line = bb.Bytes()
 	}
 
+	line, isDropped = docp.doc.applyAttributeMissing(line, file, lineNum, false)
+	if isDropped {
+		return ``, ``, true
+	}
+
 	line = bytes.TrimSpace(line)
 	value = string(line)
 
@@ -284,9 +574,11 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 		logp = `parseBlock`
 		el   = &element{}
 
-		line   []byte
-		isTerm bool
-		ok     bool
+		from    string
+		line    []byte
+		lineNum int
+		isTerm  bool
+		ok      bool
 	)
 	for !isTerm {
 		if len(line) == 0 {
@@ -366,7 +658,7 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 			continue
 
 		case lineKindInclude:
-			var elInclude = parseInclude(docp.doc, []byte(line))
+			var elInclude = docp.parseIncludeLine(line)
 
 			if elInclude == nil {
 				el.Write(line)
@@ -391,6 +683,10 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 			var (
 				key, value, ok = docp.parseAttribute(line, false)
 			)
+			if ok && len(key) == 0 {
+				line = nil
+				continue
+			}
 			if ok {
 				if key == attrNameIcons {
 					if el.Attrs == nil {
@@ -417,7 +713,7 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 			el.parseElementAttribute(line)
 			if el.style > 0 {
 				if isStyleAdmonition(el.style) {
-					el.setStyleAdmonition(el.rawStyle)
+					el.setStyleAdmonition(docp.doc, el.rawStyle)
 				}
 			}
 			line = nil
@@ -442,7 +738,7 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 		case lineKindAdmonition:
 			el.kind = elKindParagraph
 			el.style |= styleAdmonition
-			el.parseLineAdmonition(line)
+			el.parseLineAdmonition(docp.doc, line)
 			line = docp.consumeLinesUntil(
 				el,
 				lineKindEmpty,
@@ -459,11 +755,17 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 			el = new(element)
 			continue
 
-		case elKindSectionL1, elKindSectionL2, elKindSectionL3, elKindSectionL4, elKindSectionL5:
+		case elKindSectionL0, elKindSectionL1, elKindSectionL2,
+			elKindSectionL3, elKindSectionL4, elKindSectionL5:
 			if parent.kind == elKindPreamble {
+				docp.lineNum--
+				// Unread the block attributes of the section,
+				// so they are not consumed by preamble.
+				for docp.lineNum > 0 && docp.isLineBlockAttribute(docp.lineNum-1) {
+					docp.lineNum--
+				}
 				docp.kind = lineKindEmpty
 				docp.prevKind = lineKindEmpty
-				docp.lineNum--
 				isTerm = true
 				continue
 			}
@@ -474,6 +776,11 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 				el = new(element)
 				continue
 			}
+			if docp.kind == elKindSectionL0 && !docp.doc.isBook() {
+				from, lineNum = docp.lineSource(docp.lineNum - 1)
+				docp.doc.addDiagnostic(from, lineNum,
+					`level 0 sections can only be used when doctype is book: %s`, line)
+			}
 
 			el.kind = docp.kind
 			// BUG: "= =a" could become "a", it should be "=a"
@@ -524,7 +831,7 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 						elKindBlockLiteral,
 						elKindBlockLiteralNamed,
 					})
-				el.raw = applySubstitutions(docp.doc, el.raw)
+				el.applyVerbatimSubs(docp.doc)
 			}
 			parent.addChild(el)
 			el = &element{}
@@ -534,7 +841,10 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 			el.kind = docp.kind
 			el.addRole(classNameLiteralBlock)
 			line = docp.consumeLinesUntil(el, docp.kind, nil)
-			el.raw = applySubstitutions(docp.doc, el.raw)
+			from, lineNum = docp.lineSource(el.lineNum - 1)
+			el.raw = preprocessBlockCode(docp.doc, el.raw, from, lineNum)
+			el.applyVerbatimSubs(docp.doc)
+			el.parseCallouts(docp.doc)
 			parent.addChild(el)
 			el = &element{}
 			continue
@@ -543,7 +853,8 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 			el.kind = docp.kind
 			el.addRole(classNameLiteralBlock)
 			line = docp.consumeLinesUntil(el, lineKindEmpty, nil)
-			el.raw = applySubstitutions(docp.doc, el.raw)
+			el.applyVerbatimSubs(docp.doc)
+			el.parseCallouts(docp.doc)
 			parent.addChild(el)
 			el = &element{}
 			continue
@@ -552,7 +863,10 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 			el.kind = docp.kind
 			el.addRole(classNameListingBlock)
 			line = docp.consumeLinesUntil(el, docp.kind, nil)
-			el.raw = preprocessBlockCode(docp.doc, el.raw)
+			from, lineNum = docp.lineSource(el.lineNum - 1)
+			el.raw = preprocessBlockCode(docp.doc, el.raw, from, lineNum)
+			el.applyVerbatimSubs(docp.doc)
+			el.parseCallouts(docp.doc)
 			parent.addChild(el)
 			el = &element{}
 			continue
@@ -570,7 +884,8 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 					elKindBlockLiteralNamed,
 					lineKindListContinue,
 				})
-			el.raw = applySubstitutions(docp.doc, el.raw)
+			el.applyVerbatimSubs(docp.doc)
+			el.parseCallouts(docp.doc)
 			parent.addChild(el)
 			el = &element{}
 			continue
@@ -578,12 +893,15 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 		case elKindBlockPassthrough:
 			el.kind = docp.kind
 			line = docp.consumeLinesUntil(el, docp.kind, nil)
+			if el.isStyleStem() {
+				el.key = stemNotation(docp.doc, el.rawStyle)
+			}
 			parent.addChild(el)
 			el = &element{}
 			continue
 
 		case elKindListOrderedItem:
-			line = docp.parseListOrdered(parent, el.rawTitle, line, term)
+			line = docp.parseListOrdered(parent, el, line, term)
 			parent.addChild(el)
 			el = &element{}
 			continue
@@ -600,6 +918,12 @@ func (docp *documentParser) parseBlock(parent *element, term int) {
 			el = &element{}
 			continue
 
+		case elKindListCalloutItem:
+			line = docp.parseListCallout(el, line, term)
+			parent.addChild(el)
+			el = &element{}
+			continue
+
 		case elKindBlockImage:
 			var lineImage = line[7:]
 			if el.parseBlockImage(docp.doc, lineImage) {
@@ -749,7 +1073,7 @@ func (docp *documentParser) parseHeader() {
 		if docp.kind == lineKindAttribute {
 			var key, value string
 			key, value, ok = docp.parseAttribute(line, false)
-			if ok {
+			if ok && len(key) > 0 {
 				_ = docp.doc.setAttribute(key, value)
 			}
 			line = nil
@@ -801,7 +1125,7 @@ func (docp *documentParser) parseListBlock() (el *element, line []byte) {
 				},
 				kind: elKindParagraph,
 			}
-			el.parseLineAdmonition(line)
+			el.parseLineAdmonition(docp.doc, line)
 			line = docp.consumeLinesUntil(
 				el,
 				lineKindEmpty,
@@ -845,7 +1169,7 @@ func (docp *documentParser) parseListBlock() (el *element, line []byte) {
 					elKindListOrderedItem,
 					elKindListUnorderedItem,
 				})
-			el.raw = applySubstitutions(docp.doc, el.raw)
+			el.applyVerbatimSubs(docp.doc)
 			break
 		}
 		if docp.kind == lineKindText {
@@ -872,7 +1196,9 @@ func (docp *documentParser) parseListBlock() (el *element, line []byte) {
 				kind: docp.kind,
 			}
 			docp.consumeLinesUntil(el, docp.kind, nil)
-			el.raw = preprocessBlockCode(docp.doc, el.raw)
+			var from, lineNum = docp.lineSource(el.lineNum - 1)
+			el.raw = preprocessBlockCode(docp.doc, el.raw, from, lineNum)
+			el.applyVerbatimSubs(docp.doc)
 			line = nil
 			break
 		}
@@ -904,21 +1230,28 @@ func (docp *documentParser) parseListDescription(parent, el *element, line []byt
 		logp = `parseListDescription`
 		list = &element{
 			elementAttribute: elementAttribute{
-				style: el.style,
+				style:   el.style,
+				options: el.options,
 			},
 			kind:     elKindListDescription,
 			rawTitle: el.rawTitle,
 		}
-		listItem = &element{
-			elementAttribute: elementAttribute{
-				style: list.style,
-			},
-			kind: elKindListDescriptionItem,
-		}
 
-		ok bool
+		listItem *element
+		ok       bool
 	)
 
+	if parent.isInSectionStyle(styleSectionGlossary) {
+		list.style |= styleSectionGlossary
+	}
+	listItem = &element{
+		elementAttribute: elementAttribute{
+			style: list.style,
+		},
+		kind:    elKindListDescriptionItem,
+		lineNum: docp.lineNum,
+	}
+
 	listItem.parseListDescriptionItem(line)
 	list.level = listItem.level
 	list.addChild(listItem)
@@ -945,7 +1278,7 @@ func (docp *documentParser) parseListDescription(parent, el *element, line []byt
 			continue
 		}
 		if docp.kind == lineKindInclude {
-			var elInclude = parseInclude(docp.doc, line)
+			var elInclude = docp.parseIncludeLine(line)
 			if elInclude == nil {
 				el.Write(line)
 				el.WriteByte('\n')
@@ -970,7 +1303,7 @@ func (docp *documentParser) parseListDescription(parent, el *element, line []byt
 			continue
 		}
 		if docp.kind == elKindListOrderedItem {
-			line = docp.parseListOrdered(listItem, ``, line, term)
+			line = docp.parseListOrdered(listItem, &element{}, line, term)
 			continue
 		}
 		if docp.kind == elKindListUnorderedItem {
@@ -982,7 +1315,8 @@ func (docp *documentParser) parseListDescription(parent, el *element, line []byt
 				elementAttribute: elementAttribute{
 					style: list.style,
 				},
-				kind: elKindListDescriptionItem,
+				kind:    elKindListDescriptionItem,
+				lineNum: docp.lineNum,
 			}
 			el.parseListDescriptionItem(line)
 			if listItem.level == el.level {
@@ -1021,7 +1355,7 @@ func (docp *documentParser) parseListDescription(parent, el *element, line []byt
 					elKindListOrderedItem,
 					elKindListUnorderedItem,
 				})
-			el.raw = applySubstitutions(docp.doc, el.raw)
+			el.applyVerbatimSubs(docp.doc)
 			listItem.addChild(el)
 			continue
 		}
@@ -1041,7 +1375,7 @@ func (docp *documentParser) parseListDescription(parent, el *element, line []byt
 					elKindListOrderedItem,
 					elKindListUnorderedItem,
 				})
-			el.raw = applySubstitutions(docp.doc, el.raw)
+			el.applyVerbatimSubs(docp.doc)
 			listItem.addChild(el)
 			continue
 		}
@@ -1073,23 +1407,132 @@ func (docp *documentParser) parseListDescription(parent, el *element, line []byt
 	return line
 }
 
+// parseListCallout parse the callout list, the list that describe the
+// callout markers in the previous listing block.
+// The list end on the first line that is not callout list item.
+func (docp *documentParser) parseListCallout(list *element, line []byte, term int) (got []byte) {
+	var (
+		logp    = `parseListCallout`
+		block   = docp.doc.callout.block
+		autoNum int
+
+		item *element
+		text []byte
+		num  int
+		ok   bool
+	)
+
+	list.kind = elKindListCallout
+
+	for {
+		if len(line) == 0 {
+			_, line, ok = docp.line(logp)
+			if !ok {
+				break
+			}
+		}
+		if docp.kind == term {
+			break
+		}
+		if docp.kind == lineKindComment {
+			line = nil
+			continue
+		}
+		if docp.kind == lineKindEmpty {
+			// Keep going, maybe next line is still a list.
+			continue
+		}
+		if docp.kind == elKindListCalloutItem {
+			num, text, _ = parseCalloutItem(line)
+			if num == 0 {
+				autoNum++
+				num = autoNum
+			}
+			item = &element{
+				kind:           elKindListCalloutItem,
+				lineNum:        docp.lineNum,
+				listItemNumber: num,
+			}
+			item.Write(text)
+			item.WriteByte('\n')
+			docp.addCalloutItem(block, item)
+			list.addChild(item)
+			line = nil
+			continue
+		}
+		if docp.kind == lineKindText && item != nil && docp.prevKind != lineKindEmpty {
+			item.Write(line)
+			item.WriteByte('\n')
+			line = nil
+			continue
+		}
+		break
+	}
+
+	var child *element
+	for child = list.child; child != nil; child = child.next {
+		child.raw = bytes.TrimRight(child.raw, "\n")
+		child.parseInlineMarkup(docp.doc, 0)
+	}
+	docp.doc.checkCallouts()
+	return line
+}
+
+// addCalloutItem link the callout list item with the markers in listing
+// block.
+func (docp *documentParser) addCalloutItem(block, item *element) {
+	var (
+		doc           = docp.doc
+		index         = doc.callout.index
+		file, lineNum = doc.lineSource(item.lineNum)
+		co            callout
+	)
+
+	if block == nil {
+		doc.addDiagnostic(file, lineNum, `no callout found for <%d>`,
+			item.listItemNumber)
+		return
+	}
+
+	item.ID = fmt.Sprintf(`CL%d-%d`, index, item.listItemNumber)
+	for _, co = range block.callouts {
+		if co.num != item.listItemNumber {
+			continue
+		}
+		item.callouts = append(item.callouts, callout{
+			id:  item.ID,
+			ref: co.id,
+			num: co.num,
+		})
+	}
+	if len(item.callouts) == 0 {
+		doc.addDiagnostic(file, lineNum, `no callout found for <%d>`,
+			item.listItemNumber)
+		return
+	}
+	doc.callout.used[item.listItemNumber] = true
+}
+
 // parseListOrdered parser the content as list until it found line that is not
 // list-item.
 // On success it will return non-empty line and terminator character.
-func (docp *documentParser) parseListOrdered(parent *element, title string, line []byte, term int) (got []byte) {
+func (docp *documentParser) parseListOrdered(parent, el *element, line []byte, term int) (got []byte) {
 	var (
 		logp       = `parseListOrdered`
 		itemNumber = 1
 		list       = &element{
+			elementAttribute: elementAttribute{
+				options: el.options,
+			},
 			kind:     elKindListOrdered,
-			rawTitle: title,
+			rawTitle: el.rawTitle,
 		}
 		listItem = &element{
 			kind:           elKindListOrderedItem,
+			lineNum:        docp.lineNum,
 			listItemNumber: itemNumber,
 		}
 
-		el             *element
 		parentListItem *element
 		ok             bool
 	)
@@ -1133,7 +1576,8 @@ func (docp *documentParser) parseListOrdered(parent *element, title string, line
 		}
 		if docp.kind == elKindListOrderedItem {
 			el = &element{
-				kind: elKindListOrderedItem,
+				kind:    elKindListOrderedItem,
+				lineNum: docp.lineNum,
 			}
 
 			el.parseListOrderedItem(line)
@@ -1159,12 +1603,13 @@ func (docp *documentParser) parseListOrdered(parent *element, title string, line
 				parentListItem = parentListItem.parent
 			}
 
-			line = docp.parseListOrdered(listItem, ``, line, term)
+			line = docp.parseListOrdered(listItem, &element{}, line, term)
 			continue
 		}
 		if docp.kind == elKindListUnorderedItem {
 			el = &element{
-				kind: elKindListUnorderedItem,
+				kind:    elKindListUnorderedItem,
+				lineNum: docp.lineNum,
 			}
 			el.parseListUnorderedItem(line)
 
@@ -1224,7 +1669,7 @@ func (docp *documentParser) parseListOrdered(parent *element, title string, line
 						elKindListOrderedItem,
 						elKindListUnorderedItem,
 					})
-				el.raw = applySubstitutions(docp.doc, el.raw)
+				el.applyVerbatimSubs(docp.doc)
 				listItem.addChild(el)
 				continue
 			}
@@ -1245,7 +1690,7 @@ func (docp *documentParser) parseListOrdered(parent *element, title string, line
 					elKindListOrderedItem,
 					elKindListUnorderedItem,
 				})
-			el.raw = applySubstitutions(docp.doc, el.raw)
+			el.applyVerbatimSubs(docp.doc)
 			listItem.addChild(el)
 			continue
 		}
@@ -1265,7 +1710,7 @@ func (docp *documentParser) parseListOrdered(parent *element, title string, line
 					elKindListOrderedItem,
 					elKindListUnorderedItem,
 				})
-			el.raw = applySubstitutions(docp.doc, el.raw)
+			el.applyVerbatimSubs(docp.doc)
 			listItem.addChild(el)
 			continue
 		}
@@ -1309,7 +1754,8 @@ func (docp *documentParser) parseListUnordered(parent, el *element, line []byte,
 		logp = `parseListUnordered`
 		list = &element{
 			elementAttribute: elementAttribute{
-				roles: []string{classNameUlist},
+				roles:   []string{classNameUlist},
+				options: el.options,
 			},
 			kind:     elKindListUnordered,
 			rawTitle: el.rawTitle,
@@ -1325,13 +1771,17 @@ func (docp *documentParser) parseListUnordered(parent, el *element, line []byte,
 	if len(el.rawStyle) > 0 {
 		list.addRole(el.rawStyle)
 		list.rawStyle = el.rawStyle
+	} else if parent.isInSectionStyle(styleSectionBibliography) {
+		list.addRole(classNameBibliography)
+		list.rawStyle = classNameBibliography
 	}
 	for _, role = range el.roles {
 		list.addRole(role)
 	}
 
 	listItem = &element{
-		kind: elKindListUnorderedItem,
+		kind:    elKindListUnorderedItem,
+		lineNum: docp.lineNum,
 	}
 	listItem.parseListUnorderedItem(line)
 	list.level = listItem.level
@@ -1378,7 +1828,8 @@ func (docp *documentParser) parseListUnordered(parent, el *element, line []byte,
 		}
 		if docp.kind == elKindListOrderedItem {
 			el = &element{
-				kind: elKindListOrderedItem,
+				kind:    elKindListOrderedItem,
+				lineNum: docp.lineNum,
 			}
 			el.parseListOrderedItem(line)
 
@@ -1397,13 +1848,14 @@ func (docp *documentParser) parseListUnordered(parent, el *element, line []byte,
 				parentListItem = parentListItem.parent
 			}
 
-			line = docp.parseListOrdered(listItem, ``, line, term)
+			line = docp.parseListOrdered(listItem, &element{}, line, term)
 			continue
 		}
 
 		if docp.kind == elKindListUnorderedItem {
 			el = &element{
-				kind: elKindListUnorderedItem,
+				kind:    elKindListUnorderedItem,
+				lineNum: docp.lineNum,
 			}
 			if len(elAttr.rawStyle) > 0 {
 				el.addRole(el.rawStyle)
@@ -1479,7 +1931,7 @@ func (docp *documentParser) parseListUnordered(parent, el *element, line []byte,
 						elKindListOrderedItem,
 						elKindListUnorderedItem,
 					})
-				el.raw = applySubstitutions(docp.doc, el.raw)
+				el.applyVerbatimSubs(docp.doc)
 				listItem.addChild(el)
 				continue
 			}
@@ -1500,7 +1952,7 @@ func (docp *documentParser) parseListUnordered(parent, el *element, line []byte,
 					elKindListOrderedItem,
 					elKindListUnorderedItem,
 				})
-			el.raw = applySubstitutions(docp.doc, el.raw)
+			el.applyVerbatimSubs(docp.doc)
 			listItem.addChild(el)
 			continue
 		}
@@ -1520,7 +1972,7 @@ func (docp *documentParser) parseListUnordered(parent, el *element, line []byte,
 					elKindListOrderedItem,
 					elKindListUnorderedItem,
 				})
-			el.raw = applySubstitutions(docp.doc, el.raw)
+			el.applyVerbatimSubs(docp.doc)
 			listItem.addChild(el)
 			continue
 		}
@@ -1733,6 +2185,10 @@ func (docp *documentParser) whatKindOfLine(line []byte) (spaces, got []byte) {
 	switch line[0] {
 	case ':':
 		docp.kind = lineKindAttribute
+	case '<':
+		if isLineCalloutItem(line) {
+			docp.kind = elKindListCalloutItem
+		}
 	case '[':
 		var (
 			l = len(line)
@@ -1781,7 +2237,7 @@ func (docp *documentParser) whatKindOfLine(line []byte) (spaces, got []byte) {
 		default:
 			return spaces, line
 		}
-		docp.kind += docp.doc.Attributes.LevelOffset
+		docp.kind += docp.doc.Attributes.LevelOffset + docp.levelOffset
 		if docp.kind < elKindSectionL0 || docp.kind > elKindSectionL5 {
 			docp.kind = elKindText
 		}
@@ -6,8 +6,10 @@ package asciidoctor
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
+	"time"
 
 	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
 )
@@ -39,6 +41,132 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+func TestOpen_intrinsicAttributes(t *testing.T) {
+	var (
+		file    = filepath.Join(t.TempDir(), `release.adoc`)
+		modTime = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		content = []byte("{docname}{docfilesuffix}, {docdate}, {docdatetime}\n" +
+			"{backend}{outfilesuffix}, {asciidoctor-version}")
+
+		doc *Document
+		err error
+	)
+
+	t.Setenv(envSourceDateEpoch, ``)
+
+	err = os.WriteFile(file, content, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chtimes(file, modTime, modTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err = Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modTime = modTime.Local()
+
+	var (
+		docDate     = modTime.Format(timeFormatDate)
+		docDatetime = modTime.Format(timeFormatDate + ` ` + timeFormatTime)
+		exp         = "\n<div class=\"paragraph\">\n<p>release.adoc, " +
+			docDate + `, ` + docDatetime + "\nhtml5.html, " + Version +
+			"</p>\n</div>"
+
+		got bytes.Buffer
+	)
+
+	test.Assert(t, `docfile`, file, doc.Attributes.Entry[docAttrDocFile])
+	test.Assert(t, `doctime`, modTime.Format(timeFormatTime),
+		doc.Attributes.Entry[docAttrDocTime])
+	test.Assert(t, `last-update-value`, docDatetime,
+		doc.Attributes.Entry[docAttrLastUpdateValue])
+
+	err = doc.ToHTMLEmbedded(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `ToHTMLEmbedded`, exp, got.String())
+}
+
+func TestOpen_sourceDateEpoch(t *testing.T) {
+	var (
+		file    = filepath.Join(t.TempDir(), `test.adoc`)
+		content = []byte("= Title\n\n{docdatetime} {localdate}")
+
+		doc  *Document
+		got  [2]bytes.Buffer
+		err  error
+		x    int
+		tmod time.Time
+	)
+
+	err = os.WriteFile(file, content, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(envSourceDateEpoch, `1767225600`)
+
+	// Convert the same file with different modification time.
+	for x = range got {
+		tmod = time.Date(2020+x, 1, 1, 0, 0, 0, 0, time.UTC)
+		err = os.Chtimes(file, tmod, tmod)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err = Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = doc.ToHTML(&got[x])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	test.Assert(t, `last-update-value`, `2026-01-01 00:00:00 Z`,
+		doc.Attributes.Entry[docAttrLastUpdateValue])
+	test.Assert(t, `ToHTML`, got[0].String(), got[1].String())
+
+	t.Setenv(envSourceDateEpoch, `invalid`)
+	doc = Parse(content)
+	var exp = []Diagnostic{{
+		Message: `invalid SOURCE_DATE_EPOCH "invalid"`,
+	}}
+	test.Assert(t, `Diagnostics`, exp, doc.Diagnostics)
+}
+
+func TestParseWithOptions_clock(t *testing.T) {
+	var (
+		now  = time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+		opts = Options{
+			Clock: func() time.Time {
+				return now
+			},
+		}
+		exp = "\n<div class=\"paragraph\">\n" +
+			"<p>2026-03-04 05:06:07 Z, 2026-03-04, 05:06:07 Z</p>\n</div>"
+
+		got bytes.Buffer
+		err error
+	)
+
+	t.Setenv(envSourceDateEpoch, `1767225600`)
+
+	var doc = ParseWithOptions([]byte(`{docdatetime}, {localdate}, {localtime}`), opts)
+
+	err = doc.ToHTMLEmbedded(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `ToHTMLEmbedded`, exp, got.String())
+}
+
 func TestParse_document_title(t *testing.T) {
 	type testCase struct {
 		content   string
@@ -31,6 +31,10 @@ type element struct {
 	// It will be set only if attribute `sectnums` is on.
 	sectnums *sectionCounters
 
+	// caption contains the prefix of section title, for example
+	// "Appendix A: ".
+	caption string
+
 	// The key and value for attribute (lineKindAttribute).
 	key   string
 	value string
@@ -40,6 +44,10 @@ type element struct {
 
 	raw []byte // Unparsed content of element.
 
+	// callouts contains the callout markers in listing block, or the
+	// markers that referenced by the callout list item.
+	callouts []callout
+
 	elementAttribute
 
 	rawLabel       bytes.Buffer
@@ -47,8 +55,19 @@ type element struct {
 	listItemNumber int // The counter for list item, start from 1.
 	kind           int
 
+	// lineNum is the line number where the raw content start, in the
+	// document content after include directives expanded, or zero if
+	// its unknown.
+	lineNum int
+
 	// List of substitutions to be applied on raw.
 	applySubs int
+
+	// subsOrder contains the order of substitutions in applySubs, as
+	// requested in the passthrough macro.
+	// If its empty, the substitutions are applied in the default
+	// order.
+	subsOrder []int
 }
 
 func (el *element) getListOrderedClass() string {
@@ -195,10 +214,39 @@ func (el *element) hasStyle(s int64) bool {
 	return el.style&s > 0
 }
 
+// isInSectionStyle return true if the element is inside the section with
+// specific style, for example "bibliography".
+func (el *element) isInSectionStyle(style int64) bool {
+	for ; el != nil; el = el.parent {
+		if el.hasStyle(style) {
+			return true
+		}
+	}
+	return false
+}
+
+// isStyleSectionSpecial return true if the section is special section,
+// except appendix.
+// The special section is not numbered.
+func (el *element) isStyleSectionSpecial() bool {
+	return el.style&(styleSectionAbstract|
+		styleSectionBibliography|
+		styleSectionColophon|
+		styleSectionDedication|
+		styleSectionGlossary|
+		styleSectionIndex|
+		styleSectionPartIntroduction|
+		styleSectionPreface) > 0
+}
+
 func (el *element) isStyleAdmonition() bool {
 	return isStyleAdmonition(el.style)
 }
 
+func (el *element) isStyleGlossary() bool {
+	return el.style&styleSectionGlossary > 0
+}
+
 func (el *element) isStyleHorizontal() bool {
 	return el.style&styleDescriptionHorizontal > 0
 }
@@ -211,6 +259,10 @@ func (el *element) isStyleQuote() bool {
 	return isStyleQuote(el.style)
 }
 
+func (el *element) isStyleStem() bool {
+	return el.style&styleStem > 0
+}
+
 func (el *element) isStyleVerse() bool {
 	return isStyleVerse(el.style)
 }
@@ -228,6 +280,17 @@ func (el *element) WriteString(s string) {
 	el.raw = append(el.raw, []byte(s)...)
 }
 
+// addSubs add the passthrough substitution sub into applySubs and record
+// its order.
+// The substitution that has been added is ignored.
+func (el *element) addSubs(sub int) {
+	if el.applySubs&sub != 0 {
+		return
+	}
+	el.applySubs |= sub
+	el.subsOrder = append(el.subsOrder, sub)
+}
+
 // addChild push the `child` to the list of current element's child.
 func (el *element) addChild(child *element) {
 	if child == nil {
@@ -250,6 +313,21 @@ func (el *element) addChild(child *element) {
 	}
 }
 
+// prependChild add the child as the first child of el.
+func (el *element) prependChild(child *element) {
+	if child == nil {
+		return
+	}
+
+	child.parent = el
+	child.prev = nil
+	child.next = el.child
+	if el.child != nil {
+		el.child.prev = child
+	}
+	el.child = child
+}
+
 // backTrimSpace remove trailing white spaces on raw field.
 func (el *element) backTrimSpace() {
 	var x = len(el.raw) - 1
@@ -306,7 +384,27 @@ func (el *element) parseInlineMarkup(doc *Document, kind int) {
 		return
 	}
 
-	var container = parseInlineMarkup(doc, el.raw)
+	var subs = el.subs(passSubNormal)
+	if subs&passSubAttr != 0 {
+		var file, lineNum = doc.lineSource(el.lineNum)
+		el.raw, _ = doc.applyAttributeMissing(el.raw, file, lineNum,
+			subs&passSubMacro != 0)
+	}
+
+	var pi = newInlineParser(doc, el.raw)
+
+	pi.subs = subs
+	pi.lineNum = el.lineNum
+	if el.hasOption(optNameHardbreaks) {
+		pi.isHardbreaks = true
+	} else if el.parent != nil && el.parent.hasOption(optNameHardbreaks) {
+		// The list item inherit the hardbreaks option from its
+		// list.
+		pi.isHardbreaks = true
+	}
+	pi.do()
+
+	var container = pi.container
 	if kind != 0 {
 		container.kind = kind
 	}
@@ -320,11 +418,11 @@ func (el *element) parseInlineMarkup(doc *Document, kind int) {
 	el.raw = nil
 }
 
-func (el *element) parseLineAdmonition(line []byte) {
+func (el *element) parseLineAdmonition(doc *Document, line []byte) {
 	var (
 		sep      = bytes.IndexByte(line, ':')
 		class    = string(bytes.ToLower(line[:sep]))
-		rawLabel = admonitionToLabel(class)
+		rawLabel = doc.admonitionLabel(class)
 	)
 
 	el.addRole(class)
@@ -503,10 +601,62 @@ func (el *element) parseSection(doc *Document, isDiscrete bool) {
 	}
 	doc.titleID[el.Text] = el.ID
 
+	if isDiscrete || !el.parseSectionSpecial(doc) {
+		return
+	}
+
 	_, ok = doc.Attributes.Entry[docAttrSectNums]
-	if ok && !isDiscrete {
+	if ok {
 		el.sectnums = doc.sectnums.set(el.level)
+		if el.level == 1 && doc.isBook() {
+			el.caption = doc.chapterCaption(el.sectnums)
+		}
+	}
+}
+
+// parseSectionSpecial set the caption for appendix section and return
+// true if the section should be numbered.
+// The special sections, like "abstract" or "glossary", and its sub sections
+// are not numbered.
+func (el *element) parseSectionSpecial(doc *Document) bool {
+	var sec = doc.sectnums
+
+	if el.level == 0 {
+		sec.special = 0
+		sec.appendix = 0
+		el.caption = doc.partCaption()
+		return false
+	}
+	if sec.special > 0 {
+		if el.level > sec.special {
+			return false
+		}
+		sec.special = 0
 	}
+	if el.level == 1 {
+		sec.appendix = 0
+	}
+	if el.isStyleSectionSpecial() {
+		sec.special = el.level
+		return false
+	}
+	if el.level != 1 || !el.hasStyle(styleSectionAppendix) {
+		return true
+	}
+
+	doc.counterAppendix++
+
+	var (
+		letter     = byte('A' + (doc.counterAppendix-1)%26)
+		caption, _ = doc.Attributes.Entry[docAttrAppendixCaption]
+	)
+	if len(caption) > 0 {
+		el.caption = fmt.Sprintf(`%s %c: `, caption, letter)
+	} else {
+		el.caption = fmt.Sprintf(`%c. `, letter)
+	}
+	sec.setAppendix(letter)
+	return false
 }
 
 func (el *element) parseStyleClass(line []byte) {
@@ -558,6 +708,11 @@ func (el *element) parseBlockVideo(doc *Document, line []byte) bool {
 }
 
 func (el *element) postParseList(doc *Document, kind int) {
+	if kind == elKindListUnorderedItem && el.rawStyle == classNameBibliography {
+		el.postParseListBibliography(doc)
+		return
+	}
+
 	var (
 		item = el.child
 		raw  []byte
@@ -697,11 +852,11 @@ func (el *element) removeLastIfEmpty() {
 	c.parent = nil
 }
 
-func (el *element) setStyleAdmonition(admName string) {
+func (el *element) setStyleAdmonition(doc *Document, admName string) {
 	admName = strings.ToLower(admName)
 	el.addRole(admName)
 
-	var rawLabel = admonitionToLabel(admName)
+	var rawLabel = doc.admonitionLabel(admName)
 	el.rawLabel.WriteString(rawLabel)
 }
 
@@ -746,6 +901,9 @@ func (el *element) toHTML(doc *Document, w io.Writer) {
 			doc.tocHTML(w)
 		}
 
+	case elKindSectionL0:
+		htmlWriteSectionPart(doc, el, w)
+
 	case elKindSectionDiscrete:
 		hmltWriteSectionDiscrete(doc, el, w)
 
@@ -768,14 +926,30 @@ func (el *element) toHTML(doc *Document, w io.Writer) {
 	case elKindLiteralParagraph, elKindBlockLiteral,
 		elKindBlockLiteralNamed,
 		elKindBlockListing, elKindBlockListingNamed:
-		htmlWriteBlockLiteral(el, w)
+		htmlWriteBlockLiteral(doc, el, w)
+
+	case elKindInlineButton:
+		htmlWriteInlineButton(el, w)
 
 	case elKindInlineImage:
 		htmlWriteInlineImage(el, w)
 
+	case elKindInlineKbd:
+		htmlWriteInlineKbd(el, w)
+
+	case elKindInlineMenu:
+		htmlWriteInlineMenu(el, w)
+
 	case elKindInlinePass:
 		htmlWriteInlinePass(doc, el, w)
 
+	case elKindInlineStem:
+		htmlWriteInlineStem(el, w)
+
+	case elKindListCallout:
+		htmlWriteListCallout(el, w)
+	case elKindListCalloutItem:
+		htmlWriteListCalloutItem(el, w)
 	case elKindListDescription:
 		htmlWriteListDescription(el, w)
 	case elKindListOrdered:
@@ -802,6 +976,8 @@ func (el *element) toHTML(doc *Document, w io.Writer) {
 			format = _htmlListDescriptionItemQandABegin
 		case el.isStyleHorizontal():
 			format = _htmlListDescriptionItemHorizontalBegin
+		case el.isStyleGlossary():
+			format = _htmlListDescriptionItemGlossaryBegin
 		default:
 			format = _htmlListDescriptionItemBegin
 		}
@@ -836,7 +1012,11 @@ func (el *element) toHTML(doc *Document, w io.Writer) {
 		}
 
 	case elKindBlockPassthrough:
-		fmt.Fprintf(w, "\n%s", el.raw)
+		if el.isStyleStem() {
+			htmlWriteBlockStem(el, w)
+		} else {
+			fmt.Fprintf(w, "\n%s", el.raw)
+		}
 
 	case elKindBlockExcerpts:
 		if el.isStyleVerse() {
@@ -861,7 +1041,13 @@ func (el *element) toHTML(doc *Document, w io.Writer) {
 
 	case elKindInlineIDShort:
 		if !doc.isForToC {
-			fmt.Fprintf(w, "<span id=%q>%s", el.ID, el.raw)
+			htmlWriteInlineSpanBegin(el, w)
+		}
+		fmt.Fprint(w, string(el.raw))
+
+	case elKindIndexTerm:
+		if !doc.isForToC {
+			fmt.Fprintf(w, "<a id=%q></a>", el.ID)
 		}
 
 	case elKindInlineParagraph:
@@ -919,6 +1105,22 @@ func (el *element) toHTML(doc *Document, w io.Writer) {
 		}
 		fmt.Fprint(w, string(el.raw))
 
+	case elKindTextMark:
+		if el.hasStyle(styleTextMark) {
+			htmlWriteTextMarkBegin(doc, el, w)
+		} else if len(el.raw) > 0 {
+			fmt.Fprint(w, "#")
+		}
+		fmt.Fprint(w, string(el.raw))
+
+	case elKindUnconstrainedMark:
+		if el.hasStyle(styleTextMark) {
+			htmlWriteTextMarkBegin(doc, el, w)
+		} else if len(el.raw) > 0 {
+			fmt.Fprint(w, "##")
+		}
+		fmt.Fprint(w, string(el.raw))
+
 	case elKindTextMono:
 		if el.hasStyle(styleTextMono) {
 			fmt.Fprint(w, "<code>")
@@ -954,10 +1156,7 @@ func (el *element) toHTML(doc *Document, w io.Writer) {
 	switch el.kind {
 	case elKindSectionL1, elKindSectionL2, elKindSectionL3,
 		elKindSectionL4, elKindSectionL5:
-		if el.kind == elKindSectionL1 {
-			fmt.Fprint(w, "\n</div>")
-		}
-		fmt.Fprint(w, "\n</div>")
+		htmlWriteSectionEnd(doc, el, w)
 
 	case elKindParagraph:
 		switch {
@@ -974,6 +1173,11 @@ func (el *element) toHTML(doc *Document, w io.Writer) {
 	case elKindListOrderedItem, elKindListUnorderedItem:
 		fmt.Fprint(w, "\n</li>")
 
+	case elKindListCallout:
+		fmt.Fprint(w, "\n</table>\n</div>")
+	case elKindListCalloutItem:
+		fmt.Fprint(w, "</td>\n</tr>")
+
 	case elKindListDescriptionItem:
 		var format string
 
@@ -1038,6 +1242,10 @@ func (el *element) toHTML(doc *Document, w io.Writer) {
 		if el.hasStyle(styleTextItalic) {
 			fmt.Fprint(w, "</em>")
 		}
+	case elKindTextMark, elKindUnconstrainedMark:
+		if el.hasStyle(styleTextMark) {
+			htmlWriteTextMarkEnd(doc, el, w)
+		}
 	case elKindTextMono, elKindUnconstrainedMono:
 		if el.hasStyle(styleTextMono) {
 			fmt.Fprint(w, "</code>")
@@ -1104,6 +1312,18 @@ func (el *element) writeText(w io.Writer) {
 		}
 		fmt.Fprint(w, string(el.raw))
 
+	case elKindTextMark:
+		if !el.hasStyle(styleTextMark) {
+			fmt.Fprint(w, "#")
+		}
+		fmt.Fprint(w, string(el.raw))
+
+	case elKindUnconstrainedMark:
+		if !el.hasStyle(styleTextMark) {
+			fmt.Fprint(w, "##")
+		}
+		fmt.Fprint(w, string(el.raw))
+
 	case elKindTextMono:
 		if !el.hasStyle(styleTextMono) {
 			fmt.Fprint(w, "`")
@@ -1131,20 +1351,3 @@ func (el *element) writeText(w io.Writer) {
 		el.next.writeText(w)
 	}
 }
-
-func admonitionToLabel(admName string) string {
-	admName = strings.ToUpper(admName)
-	switch admName {
-	case admonitionCaution:
-		return "Caution"
-	case admonitionImportant:
-		return "Important"
-	case admonitionNote:
-		return "Note"
-	case admonitionTip:
-		return "Tip"
-	case admonitionWarning:
-		return "Warning"
-	}
-	return admName
-}
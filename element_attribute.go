@@ -31,6 +31,17 @@ func (ea *elementAttribute) addRole(role string) {
 	ea.roles = libstrings.AppendUniq(ea.roles, role)
 }
 
+// hasOption return true if the option name is set.
+func (ea *elementAttribute) hasOption(name string) bool {
+	var opt string
+	for _, opt = range ea.options {
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (ea *elementAttribute) htmlClasses() string {
 	if len(ea.roles) == 0 {
 		return ``
@@ -109,10 +120,20 @@ func (ea *elementAttribute) parseElementAttribute(raw []byte) {
 			buf = buf[:0]
 			prevc = c
 		case '#', '%':
+			if bytes.IndexByte(buf, '=') > 0 {
+				// The character is part of named value.
+				buf = append(buf, c)
+				continue
+			}
 			ea.setByPreviousChar(prevc, string(bytes.TrimSpace(buf)))
 			buf = buf[:0]
 			prevc = c
 		case '.':
+			if bytes.IndexByte(buf, '=') > 0 {
+				// The character is part of named value.
+				buf = append(buf, c)
+				continue
+			}
 			if ea.style == styleQuote || ea.style == styleVerse {
 				// Make the '.' as part of attribution.
 				if prevc == ',' {
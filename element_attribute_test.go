@@ -66,6 +66,15 @@ func Test_parseElementAttribute(t *testing.T) {
 			style:    styleQuote,
 			pos:      2,
 		},
+	}, {
+		raw: `[lines=1..5;10..-1,width=50%]`,
+		exp: elementAttribute{
+			Attrs: map[string]string{
+				attrNameLines: `1..5;10..-1`,
+				attrNameWidth: `50%`,
+			},
+			pos: 1,
+		},
 	}}
 
 	var (
@@ -5,53 +5,584 @@ package asciidoctor
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/ascii"
+	libstrings "git.sr.ht/~shulhan/pakakeh.go/lib/strings"
 )
 
 type elementInclude struct {
+	// from is the path of file that contains the directive.
+	from    string
 	fpath   string
 	content []byte
 	attrs   elementAttribute
+
+	// lineNums contains the line number in the included file for each
+	// line in content.
+	// It is nil if the content is not filtered by "lines" or "tags".
+	lineNums []int
+
+	// lineNum is the line number of directive in the from file.
+	lineNum int
+
+	// isPlaceholder is true if the content is not from the included
+	// file but replacement of the directive, for example link to the
+	// URI or the text "Unresolved directive".
+	isPlaceholder bool
 }
 
-func parseInclude(doc *Document, line []byte) (el *elementInclude) {
+// parseInclude parse the "include::" directive in line and read the content
+// of included file.
+// It will return nil if the line is not valid include directive.
+//
+// If the file cannot be read and the directive has option "optional", the
+// content will be empty.
+// Otherwise, the content is replaced with text "Unresolved directive in
+// <file> - <line>" and the problem is reported as Diagnostic.
+//
+// The from is the path of file that contains the directive, and the
+// lineNum is the line number of directive, or zero if its unknown.
+// If the target contains reference to missing attribute and the
+// "attribute-missing" is "drop-line", the directive is removed.
+func parseInclude(doc *Document, line []byte, from string, lineNum int) (el *elementInclude) {
 	var (
 		path  []byte
 		start int
 		end   int
 		err   error
+		ok    bool
 	)
 
 	if !bytes.HasPrefix(line, []byte(prefixInclude)) {
 		return nil
 	}
 
-	el = &elementInclude{}
-	line = line[len(prefixInclude):]
+	el = &elementInclude{
+		from:    from,
+		lineNum: lineNum,
+	}
+
+	var target = line[len(prefixInclude):]
 
-	path, start = indexByteUnescape(line, '[')
+	path, start = indexByteUnescape(target, '[')
 	if start == -1 {
 		return nil
 	}
 
-	_, end = indexByteUnescape(line[start:], ']')
+	_, end = indexByteUnescape(target[start:], ']')
 	if end == -1 {
 		return nil
 	}
 
-	el.attrs.parseElementAttribute(line[start : start+end+1])
+	el.attrs.parseElementAttribute(target[start : start+end+1])
+
+	var isDropped bool
+	path, isDropped = doc.applyAttributeMissing(path, from, lineNum, false)
+	if isDropped {
+		return el
+	}
+
+	var newPath = string(applySubstitutions(doc, path))
 
-	var newPath = applySubstitutions(doc, path)
-	if bytes.Contains(path, []byte(docAttrDocdir)) {
-		el.fpath = string(newPath)
+	if isURI(newPath) {
+		el.fpath = newPath
+		_, ok = doc.Attributes.Entry[docAttrAllowURIRead]
+		if !ok || doc.includeResolver == nil {
+			// Replace the directive with link to the URI.
+			el.content = fmt.Appendf(nil, "link:%s[role=include]\n", newPath)
+			el.isPlaceholder = true
+			return el
+		}
+		el.content, err = doc.includeResolver(newPath, el.attrs.Attrs, from)
 	} else {
-		el.fpath = filepath.Join(doc.docdir, string(newPath))
+		if bytes.Contains(path, []byte(docAttrDocdir)) {
+			el.fpath = newPath
+		} else {
+			el.fpath = filepath.Join(doc.docdir, newPath)
+		}
+		if doc.includeResolver != nil {
+			el.content, err = doc.includeResolver(newPath, el.attrs.Attrs, from)
+		} else {
+			el.content, err = os.ReadFile(el.fpath)
+		}
 	}
-	el.content, err = os.ReadFile(el.fpath)
 	if err != nil {
-		return nil
+		el.unresolved(doc, line, err)
+		return el
+	}
+	el.content = bytes.ReplaceAll(el.content, []byte("\r\n"), []byte("\n"))
+
+	var v string
+
+	v, ok = el.attrs.Attrs[attrNameLines]
+	if ok {
+		el.content, el.lineNums = includeLines(el.content, v)
+	} else {
+		v, ok = el.attrs.Attrs[attrNameTags]
+		if !ok {
+			v, ok = el.attrs.Attrs[attrNameTag]
+		}
+		if ok {
+			el.content, el.lineNums = el.includeTags(doc, v)
+		}
+	}
+
+	v, ok = el.attrs.Attrs[attrNameIndent]
+	if ok {
+		var indent int64
+		indent, err = strconv.ParseInt(v, 10, 32)
+		if err == nil && indent >= 0 {
+			el.content = includeIndent(el.content, int(indent))
+		}
 	}
 
 	return el
 }
+
+// isURI return true if the include target is an URI, for example
+// "https://example.com/file.adoc".
+func isURI(target string) bool {
+	var (
+		scheme, _, ok = strings.Cut(target, `://`)
+		c             byte
+		x             int
+	)
+	if !ok || len(scheme) < 2 {
+		return false
+	}
+	for x = 0; x < len(scheme); x++ {
+		c = scheme[x]
+		if ascii.IsAlpha(c) {
+			continue
+		}
+		if x > 0 && (ascii.IsDigit(c) || c == '+' || c == '-' || c == '.') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// unresolved set the content of missing include file.
+func (el *elementInclude) unresolved(doc *Document, line []byte, err error) {
+	if libstrings.IsContain(el.attrs.options, optNameOptional) {
+		el.content = nil
+		return
+	}
+
+	var docname = `<stdin>`
+	if len(el.from) != 0 {
+		docname = filepath.Base(el.from)
+	}
+
+	el.isPlaceholder = true
+	el.content = fmt.Appendf(nil, "Unresolved directive in %s - %s\n",
+		docname, bytes.TrimSpace(line))
+
+	if errors.Is(err, fs.ErrNotExist) {
+		doc.addDiagnostic(el.from, el.lineNum, `include file not found: %s`, el.fpath)
+	} else {
+		doc.addDiagnostic(el.from, el.lineNum, `include file not readable: %s`, err)
+	}
+}
+
+// levelOffset return the value of "leveloffset" attribute.
+// The isRelative is true if the value prefixed with "+" or "-".
+// It will return ok as false if the attribute is not set or its value is
+// not a number.
+func (el *elementInclude) levelOffset() (offset int, isRelative, ok bool) {
+	var v string
+
+	v, ok = el.attrs.Attrs[docAttrLevelOffset]
+	if !ok || len(v) == 0 {
+		return 0, false, false
+	}
+
+	var (
+		n   int64
+		err error
+	)
+	n, err = strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return 0, false, false
+	}
+	isRelative = v[0] == '+' || v[0] == '-'
+	return int(n), isRelative, true
+}
+
+// includeIndent remove the common leading white spaces in all non-empty
+// lines in content and then indent them with n spaces.
+func includeIndent(content []byte, n int) (out []byte) {
+	var (
+		lines  = bytes.Split(bytes.TrimSuffix(content, []byte{'\n'}), []byte{'\n'})
+		common = -1
+
+		line []byte
+		x    int
+	)
+
+	for _, line = range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		x = len(line) - len(bytes.TrimLeft(line, " \t"))
+		if common < 0 || x < common {
+			common = x
+		}
+	}
+	if common < 0 {
+		return content
+	}
+
+	var prefix = bytes.Repeat([]byte{' '}, n)
+
+	out = make([]byte, 0, len(content)+len(lines)*n)
+	for _, line = range lines {
+		if len(bytes.TrimSpace(line)) != 0 {
+			out = append(out, prefix...)
+			out = append(out, line[common:]...)
+		}
+		out = append(out, '\n')
+	}
+	return out
+}
+
+// includeTags select the lines in the content based on tagged regions.
+// A tagged region is started by line that contains "tag::NAME[]" and
+// ended by line that contains "end::NAME[]".
+// The tag lines itself are not included.
+//
+// The tags value contains list of tag names separated by ";" or ",".
+// A tag name prefixed with "!" exclude the region.
+// The wildcard "**" select all lines, including the lines outside of tagged
+// regions, and "*" select all tagged regions.
+//
+// It return the selected lines along with their line numbers.
+// Any unclosed tag, mismatched end tag, or missing tag is reported as
+// Diagnostic.
+func (el *elementInclude) includeTags(doc *Document, tags string) (out []byte, lineNums []int) {
+	type activeTag struct {
+		name     string
+		lineNum  int
+		isSelect bool
+	}
+
+	var (
+		inTags  = map[string]bool{}
+		inOrder []string
+
+		name string
+	)
+
+	for _, name = range strings.FieldsFunc(tags, func(r rune) bool {
+		return r == ';' || r == ','
+	}) {
+		name = strings.TrimSpace(name)
+		if len(name) == 0 {
+			continue
+		}
+		if name[0] == '!' {
+			name = name[1:]
+			inTags[name] = false
+		} else {
+			inTags[name] = true
+		}
+		inOrder = append(inOrder, name)
+	}
+	if len(inTags) == 0 {
+		return el.content, nil
+	}
+
+	var (
+		baseSelect     bool
+		hasWildcard    bool
+		wildcardSelect bool
+		isSelect       bool
+		ok             bool
+	)
+
+	baseSelect, ok = inTags[`**`]
+	if ok {
+		delete(inTags, `**`)
+		wildcardSelect, hasWildcard = inTags[`*`]
+		if hasWildcard {
+			delete(inTags, `*`)
+		} else if !baseSelect {
+			// "!**" followed by negated tag select all tagged
+			// regions except the negated one.
+			for _, name = range inOrder {
+				if name == `**` {
+					continue
+				}
+				if !inTags[name] {
+					hasWildcard = true
+					wildcardSelect = true
+				}
+				break
+			}
+		}
+	} else if wildcardSelect, hasWildcard = inTags[`*`]; hasWildcard {
+		delete(inTags, `*`)
+		baseSelect = inOrder[0] == `*` && !wildcardSelect
+	} else {
+		baseSelect = true
+		for _, isSelect = range inTags {
+			if isSelect {
+				baseSelect = false
+				break
+			}
+		}
+	}
+
+	var (
+		lines    = bytes.Split(bytes.TrimSuffix(el.content, []byte{'\n'}), []byte{'\n'})
+		stack    []activeTag
+		used     = map[string]bool{}
+		active   *activeTag
+		line     []byte
+		x        int
+		isEndTag bool
+	)
+
+	isSelect = baseSelect
+	out = make([]byte, 0, len(el.content))
+
+	for x, line = range lines {
+		name, isEndTag = parseTagDirective(line)
+		if len(name) == 0 {
+			if isSelect {
+				out = append(out, line...)
+				out = append(out, '\n')
+				lineNums = append(lineNums, x+1)
+			}
+			continue
+		}
+		if isEndTag {
+			if active != nil && name == active.name {
+				stack = stack[:len(stack)-1]
+				if len(stack) == 0 {
+					active = nil
+					isSelect = baseSelect
+				} else {
+					active = &stack[len(stack)-1]
+					isSelect = active.isSelect
+				}
+				continue
+			}
+			_, ok = inTags[name]
+			if !ok {
+				continue
+			}
+			if active != nil {
+				doc.addDiagnostic(el.fpath, x+1,
+					`mismatched end tag %q, expecting %q`,
+					name, active.name)
+			} else {
+				doc.addDiagnostic(el.fpath, x+1,
+					`unexpected end tag %q`, name)
+			}
+			continue
+		}
+
+		var isTagSelect bool
+		isTagSelect, ok = inTags[name]
+		if ok {
+			used[name] = true
+		} else if hasWildcard {
+			if active != nil && !isSelect {
+				isTagSelect = false
+			} else {
+				isTagSelect = wildcardSelect
+			}
+		} else {
+			continue
+		}
+		isSelect = isTagSelect
+		stack = append(stack, activeTag{
+			name:     name,
+			lineNum:  x + 1,
+			isSelect: isSelect,
+		})
+		active = &stack[len(stack)-1]
+	}
+
+	for x = len(stack) - 1; x >= 0; x-- {
+		doc.addDiagnostic(el.fpath, stack[x].lineNum,
+			`unclosed tag %q`, stack[x].name)
+	}
+	for _, name = range inOrder {
+		if name == `*` || name == `**` || used[name] {
+			continue
+		}
+		_, ok = inTags[name]
+		if !ok {
+			continue
+		}
+		doc.addDiagnostic(el.fpath, 0, `tag %q not found`, name)
+		used[name] = true
+	}
+	return out, lineNums
+}
+
+// parseTagDirective find the tag directive "tag::NAME[]" or "end::NAME[]"
+// in the line.
+// The directive must be the last word in the line, usually after the
+// comment.
+// It will return the tag name and true if the directive is "end::".
+func parseTagDirective(line []byte) (name string, isEnd bool) {
+	var (
+		x   int
+		idx int
+		pre []byte
+	)
+
+	for x < len(line) {
+		idx = bytes.Index(line[x:], []byte(`::`))
+		if idx < 3 {
+			if idx < 0 {
+				return ``, false
+			}
+			x += idx + 2
+			continue
+		}
+		idx += x
+
+		pre = line[idx-3 : idx]
+		switch string(pre) {
+		case attrNameTag:
+			isEnd = false
+		case attrNameEnd:
+			isEnd = true
+		default:
+			x = idx + 2
+			continue
+		}
+		if idx-3 > 0 && isTagNameChar(line[idx-4]) {
+			// Not a word boundary.
+			x = idx + 2
+			continue
+		}
+
+		var (
+			rest = line[idx+2:]
+			end  = bytes.Index(rest, []byte(`[]`))
+		)
+		if end <= 0 || bytes.ContainsAny(rest[:end], " \t") {
+			x = idx + 2
+			continue
+		}
+		var after = rest[end+2:]
+		if len(after) != 0 && after[0] != ' ' && after[0] != '\t' {
+			x = idx + 2
+			continue
+		}
+		return string(rest[:end]), isEnd
+	}
+	return ``, false
+}
+
+func isTagNameChar(c byte) bool {
+	return c == '_' || ascii.IsAlnum(c)
+}
+
+// lineRange contains the range of line number, start from 1.
+// The end is -1 if the range is until the end of content.
+type lineRange struct {
+	start int
+	end   int
+}
+
+// parseLineRanges parse list of line ranges,
+//
+//	LINES      = LINE_RANGE *( (";" / ",") LINE_RANGE )
+//
+//	LINE_RANGE = NUMBER [ ".." [ NUMBER ] ]
+//
+// The end of range can be -1 or empty to select until the end of content.
+// Invalid range is ignored.
+func parseLineRanges(v string) (ranges []lineRange) {
+	var (
+		fields = strings.FieldsFunc(v, func(r rune) bool {
+			return r == ';' || r == ','
+		})
+
+		field string
+		err   error
+	)
+
+	for _, field = range fields {
+		var (
+			rawStart, rawEnd, isRange = strings.Cut(strings.TrimSpace(field), `..`)
+			lr                        lineRange
+		)
+
+		lr.start, err = strconv.Atoi(rawStart)
+		if err != nil {
+			continue
+		}
+		lr.end = lr.start
+		if isRange {
+			lr.end = -1
+			if len(rawEnd) != 0 {
+				lr.end, err = strconv.Atoi(rawEnd)
+				if err != nil {
+					continue
+				}
+			}
+		}
+		ranges = append(ranges, lr)
+	}
+	return ranges
+}
+
+// isLineInRanges return true if the line number n is inside one of the
+// ranges.
+func isLineInRanges(ranges []lineRange, n int) bool {
+	var lr lineRange
+	for _, lr = range ranges {
+		if n < lr.start {
+			continue
+		}
+		if lr.end >= 0 && n > lr.end {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// includeLines select the lines in content based on list of line ranges,
+// see [parseLineRanges] for its format.
+// The line number start from 1.
+// The selected lines are returned in the same order as in the content,
+// along with their line numbers.
+func includeLines(content []byte, v string) (out []byte, lineNums []int) {
+	var ranges = parseLineRanges(v)
+	if len(ranges) == 0 {
+		return content, nil
+	}
+
+	var (
+		lines = bytes.Split(bytes.TrimSuffix(content, []byte{'\n'}), []byte{'\n'})
+
+		line []byte
+		x    int
+	)
+
+	out = make([]byte, 0, len(content))
+	for x, line = range lines {
+		if isLineInRanges(ranges, x+1) {
+			out = append(out, line...)
+			out = append(out, '\n')
+			lineNums = append(lineNums, x+1)
+		}
+	}
+	return out, lineNums
+}
@@ -6,6 +6,7 @@ package asciidoctor
 
 import (
 	"bytes"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -45,3 +46,313 @@ func TestParseIncludeWithAbsolutePath(t *testing.T) {
 	var exp = string(tdata.Output[`include`])
 	test.Assert(t, `ParseIncludeWithAbsolutePath`, exp, got.String())
 }
+
+func TestParseIncludeTagsDiagnostics(t *testing.T) {
+	type testCase struct {
+		desc    string
+		content string
+		expText string
+		exp     []Diagnostic
+	}
+
+	var (
+		fpath = `testdata/_includes/tagged_unclosed.adoc`
+
+		cases = []testCase{{
+			desc:    `unclosed tags`,
+			content: `include::` + fpath + `[tags=open;missing_end]`,
+			expText: "Inside open.\n",
+			exp: []Diagnostic{{
+				File:    fpath,
+				Line:    4,
+				Message: `unclosed tag "missing_end"`,
+			}, {
+				File:    fpath,
+				Line:    2,
+				Message: `unclosed tag "open"`,
+			}},
+		}, {
+			desc:    `tag not found`,
+			content: `include::` + fpath + `[tag=notexist]`,
+			exp: []Diagnostic{{
+				File:    fpath,
+				Message: `tag "notexist" not found`,
+			}},
+		}}
+
+		c  testCase
+		el *elementInclude
+	)
+
+	for _, c = range cases {
+		var doc = newDocument()
+
+		el = parseInclude(doc, []byte(c.content), doc.file, 3)
+		test.Assert(t, c.desc+`: content`, c.expText, string(el.content))
+		test.Assert(t, c.desc, c.exp, doc.Diagnostics)
+	}
+}
+
+func TestParseIncludeMissing(t *testing.T) {
+	type testCase struct {
+		desc    string
+		content string
+		expText string
+		exp     []Diagnostic
+	}
+
+	var (
+		fpath = `testdata/_includes/missing.adoc`
+
+		cases = []testCase{{
+			desc:    `optional`,
+			content: `include::` + fpath + `[opts=optional]`,
+		}, {
+			desc:    `required`,
+			content: `include::` + fpath + `[]`,
+			expText: "Unresolved directive in <stdin> - include::" + fpath + "[]\n",
+			exp: []Diagnostic{{
+				Line:    3,
+				Message: `include file not found: ` + fpath,
+			}},
+		}}
+
+		c  testCase
+		el *elementInclude
+	)
+
+	for _, c = range cases {
+		var doc = newDocument()
+
+		el = parseInclude(doc, []byte(c.content), doc.file, 3)
+		test.Assert(t, c.desc+`: content`, c.expText, string(el.content))
+		test.Assert(t, c.desc, c.exp, doc.Diagnostics)
+	}
+}
+
+func TestParseIncludeMissing_lineNum(t *testing.T) {
+	var (
+		content = "= Title\n\nPara.\n\n" +
+			"include::testdata/_includes/missing.adoc[]\n\n" +
+			"----\ninclude::testdata/_includes/missing2.adoc[]\n----\n"
+
+		doc = Parse([]byte(content))
+
+		exp = []Diagnostic{{
+			Line:    5,
+			Message: `include file not found: testdata/_includes/missing.adoc`,
+		}, {
+			Line:    8,
+			Message: `include file not found: testdata/_includes/missing2.adoc`,
+		}}
+	)
+
+	test.Assert(t, `Diagnostics`, exp, doc.Diagnostics)
+}
+
+func TestParseWithOptionsIncludeResolver(t *testing.T) {
+	type testCase struct {
+		desc         string
+		content      string
+		exp          string
+		allowURIRead bool
+	}
+
+	var (
+		fixtures = map[string]string{
+			`chapter.adoc`:                    `Content of chapter.`,
+			`https://example.com/remote.adoc`: `Content of remote.`,
+		}
+		opts = Options{
+			IncludeResolver: func(target string, _ map[string]string, _ string) ([]byte, error) {
+				var content, ok = fixtures[target]
+				if !ok {
+					return nil, fs.ErrNotExist
+				}
+				return []byte(content), nil
+			},
+		}
+
+		cases = []testCase{{
+			desc:    `from resolver`,
+			content: `include::chapter.adoc[]`,
+			exp: `
+<div class="paragraph">
+<p>Content of chapter.</p>
+</div>`,
+		}, {
+			desc:    `not found in resolver`,
+			content: `include::notexist.adoc[]`,
+			exp: `
+<div class="paragraph">
+<p>Unresolved directive in &lt;stdin&gt; - include::notexist.adoc[]</p>
+</div>`,
+		}, {
+			desc:    `URI without allow-uri-read`,
+			content: `include::https://example.com/remote.adoc[]`,
+			exp: `
+<div class="paragraph">
+<p><a href="https://example.com/remote.adoc" class="include">https://example.com/remote.adoc</a></p>
+</div>`,
+		}, {
+			desc: `URI with allow-uri-read in document`,
+			content: `:allow-uri-read:
+
+include::https://example.com/remote.adoc[]`,
+			exp: `
+<div class="paragraph">
+<p><a href="https://example.com/remote.adoc" class="include">https://example.com/remote.adoc</a></p>
+</div>`,
+		}, {
+			desc:         `URI with AllowURIRead`,
+			content:      `include::https://example.com/remote.adoc[]`,
+			allowURIRead: true,
+			exp: `
+<div class="paragraph">
+<p>Content of remote.</p>
+</div>`,
+		}, {
+			desc: `URI with AllowURIRead unset in document`,
+			content: `:allow-uri-read!:
+
+include::https://example.com/remote.adoc[]`,
+			allowURIRead: true,
+			exp: `
+<div class="paragraph">
+<p>Content of remote.</p>
+</div>`,
+		}}
+
+		c   testCase
+		doc *Document
+		got bytes.Buffer
+		err error
+	)
+
+	for _, c = range cases {
+		opts.AllowURIRead = c.allowURIRead
+		doc = ParseWithOptions([]byte(c.content), opts)
+		got.Reset()
+		err = doc.ToHTMLEmbedded(&got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.Assert(t, c.desc, c.exp, got.String())
+	}
+}
+
+func TestParseWithOptionsIncludeResolver_from(t *testing.T) {
+	type include struct {
+		target string
+		from   string
+	}
+
+	var (
+		fixtures = map[string]string{
+			`chapter.adoc`:          "include::section.adoc[]\n",
+			`section.adoc`:          `Content of section.`,
+			`https://example.com/a`: "include::b.adoc[]\n",
+			`b.adoc`:                `Content of b.`,
+		}
+
+		got []include
+
+		opts = Options{
+			IncludeResolver: func(target string, _ map[string]string, from string) ([]byte, error) {
+				got = append(got, include{target: target, from: from})
+				return []byte(fixtures[target]), nil
+			},
+			AllowURIRead: true,
+		}
+		content = []byte(`include::chapter.adoc[]
+
+include::https://example.com/a[]
+`)
+		exp = []include{{
+			target: `chapter.adoc`,
+		}, {
+			target: `section.adoc`,
+			from:   `chapter.adoc`,
+		}, {
+			target: `https://example.com/a`,
+		}, {
+			target: `b.adoc`,
+			from:   `https://example.com/a`,
+		}}
+	)
+
+	ParseWithOptions(content, opts)
+	test.Assert(t, `IncludeResolver`, exp, got)
+}
+
+func TestParseWithOptionsIncludeResolver_lineSource(t *testing.T) {
+	var (
+		fixtures = map[string]string{
+			`a.adoc`: "A1.\nA2.\nA3.\n",
+			`d.adoc`: "D1.\n\nD3.\n\nSee <<other>>.\n",
+			`inc/c.adoc`: `See <<nope>>.
+
+----
+a <1>
+----
+
+ifdef::x[]
+C2.
+`,
+		}
+
+		opts = Options{
+			IncludeResolver: func(target string, _ map[string]string, _ string) ([]byte, error) {
+				var content, ok = fixtures[target]
+				if !ok {
+					return nil, fs.ErrNotExist
+				}
+				return []byte(content), nil
+			},
+		}
+		content = []byte(`= Title
+
+include::a.adoc[]
+
+include::missing.adoc[]
+
+= Part
+
+See <<nowhere>>.
+
+include::d.adoc[lines=3..5]
+
+include::inc/c.adoc[]
+`)
+		exp = []Diagnostic{{
+			Line:    5,
+			Message: `include file not found: missing.adoc`,
+		}, {
+			Line:    7,
+			Message: `level 0 sections can only be used when doctype is book: = Part`,
+		}, {
+			File:    `inc/c.adoc`,
+			Line:    7,
+			Message: `unterminated conditional directive: ifdef::x[]`,
+		}, {
+			File:    `inc/c.adoc`,
+			Line:    4,
+			Message: `callout <1> does not have item in callout list`,
+		}, {
+			Line:    9,
+			Message: `possible invalid reference: nowhere`,
+		}, {
+			File:    `d.adoc`,
+			Line:    5,
+			Message: `possible invalid reference: other`,
+		}, {
+			File:    `inc/c.adoc`,
+			Line:    1,
+			Message: `possible invalid reference: nope`,
+		}}
+
+		doc = ParseWithOptions(content, opts)
+	)
+
+	test.Assert(t, `Diagnostics`, exp, doc.Diagnostics)
+}
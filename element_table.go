@@ -23,6 +23,10 @@ type elementTable struct {
 
 	hasHeader bool
 	hasFooter bool
+
+	// isHardbreaks set if the table has option "hardbreaks", to keep
+	// the line feed inside the cells as line break.
+	isHardbreaks bool
 }
 
 func newTable(ea *elementAttribute, content []byte) (table *elementTable) {
@@ -203,10 +207,20 @@ func (table *elementTable) parseOptions(opts []string) {
 			table.hasHeader = true
 		case attrValueFooter:
 			table.hasFooter = true
+		case optNameHardbreaks:
+			table.isHardbreaks = true
 		}
 	}
 }
 
+// parseCellMarkup parse the inline markup in the cell content.
+func (table *elementTable) parseCellMarkup(doc *Document, content []byte) (container *element) {
+	if table.isHardbreaks {
+		return parseInlineMarkupHardbreaks(doc, content)
+	}
+	return parseInlineMarkup(doc, content)
+}
+
 func (table *elementTable) recalculateWidth() {
 	var (
 		totalWidth = big.NewRat(0)
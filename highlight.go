@@ -0,0 +1,632 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/ascii"
+	libstrings "git.sr.ht/~shulhan/pakakeh.go/lib/strings"
+)
+
+// sourceHighlighterBuiltin is the value of document attribute
+// "source-highlighter" to highlight the source block using the built-in
+// highlighter.
+const sourceHighlighterBuiltin = `builtin`
+
+// List of HTML class for token in highlighted source.
+const (
+	tokenClassBuiltin  = `tok-builtin`
+	tokenClassComment  = `tok-comment`
+	tokenClassHeading  = `tok-heading`
+	tokenClassKey      = `tok-key`
+	tokenClassKeyword  = `tok-keyword`
+	tokenClassMeta     = `tok-meta`
+	tokenClassNumber   = `tok-number`
+	tokenClassString   = `tok-string`
+	tokenClassVariable = `tok-variable`
+)
+
+// List of class names for highlighted source block.
+const (
+	classNameHighlightLine = `hll`
+)
+
+// highlightSyntax define the lexical rules of programming language for
+// built-in source highlighter.
+type highlightSyntax struct {
+	keywords map[string]bool
+	builtins map[string]bool
+
+	// quotes contains the characters that start and end a string.
+	quotes string
+
+	// multilineQuotes contains the quote characters that can span
+	// multiple lines, for example "`" in Go.
+	multilineQuotes string
+
+	// identChars contains additional characters, other than letter,
+	// digit, and "_", that can be part of identifier.
+	identChars string
+
+	lineComments  []string
+	blockComments [][2]string
+
+	// varPrefix is the character that start a variable, for example
+	// "$" in shell.
+	varPrefix byte
+
+	// isKeywordFold define whether the keywords are case insensitive.
+	isKeywordFold bool
+
+	// isCommentOnWord define whether the line comment must be placed at
+	// the start of line or after white space, for example "#" in
+	// shell.
+	isCommentOnWord bool
+
+	// hasKey define whether the string or identifier followed by ":"
+	// is highlighted as key, for example in JSON and YAML.
+	hasKey bool
+
+	// hasTripleQuote define whether the string can be quoted using
+	// three quote characters, for example in Python.
+	hasTripleQuote bool
+}
+
+// highlightSyntaxes contains mapping of source language, and its aliases,
+// to its syntax.
+var highlightSyntaxes = map[string]*highlightSyntax{}
+
+func init() {
+	var (
+		syntaxGo = &highlightSyntax{
+			keywords: newWordSet(`break case chan const continue default
+				defer else fallthrough for func go goto if import
+				interface map package range return select struct
+				switch type var`),
+			builtins: newWordSet(`any append bool byte cap clear close
+				comparable complex complex64 complex128 copy delete
+				error false float32 float64 imag int int8 int16
+				int32 int64 iota len make max min new nil panic
+				print println real recover rune string true uint
+				uint8 uint16 uint32 uint64 uintptr`),
+			quotes:          "\"'`",
+			multilineQuotes: "`",
+			lineComments:    []string{`//`},
+			blockComments:   [][2]string{{`/*`, `*/`}},
+		}
+		syntaxShell = &highlightSyntax{
+			keywords: newWordSet(`break case continue do done elif else
+				esac export fi for function if in local return
+				select then until while`),
+			builtins: newWordSet(`alias cd echo eval exec exit printf
+				read set shift source test trap unset`),
+			quotes:          `"'`,
+			identChars:      `-`,
+			lineComments:    []string{`#`},
+			varPrefix:       '$',
+			isCommentOnWord: true,
+		}
+		syntaxJSON = &highlightSyntax{
+			builtins: newWordSet(`false null true`),
+			quotes:   `"`,
+			hasKey:   true,
+		}
+		syntaxYAML = &highlightSyntax{
+			builtins:        newWordSet(`false no null true yes`),
+			quotes:          `"'`,
+			identChars:      `-.`,
+			lineComments:    []string{`#`},
+			isCommentOnWord: true,
+			hasKey:          true,
+		}
+		syntaxSQL = &highlightSyntax{
+			keywords: newWordSet(`add all alter and as asc begin between
+				by case check column commit constraint create
+				database default delete desc distinct drop else end
+				exists foreign from full group having if in index
+				inner insert into is join key left like limit not
+				null offset on or order outer primary references
+				right rollback select set table then union unique
+				update values view when where with`),
+			builtins: newWordSet(`avg bigint boolean char count date
+				decimal float int integer max min now numeric serial
+				sum text timestamp varchar`),
+			quotes:        `'`,
+			lineComments:  []string{`--`},
+			blockComments: [][2]string{{`/*`, `*/`}},
+			isKeywordFold: true,
+		}
+		syntaxJavaScript = &highlightSyntax{
+			keywords: newWordSet(`async await break case catch class
+				const continue debugger default delete do else
+				export extends finally for function if import in
+				instanceof let new of return static super switch
+				this throw try typeof var void while yield`),
+			builtins: newWordSet(`Array Boolean Date Error JSON Map Math
+				Number Object Promise Set String console document
+				false null true undefined window`),
+			quotes:          "\"'`",
+			multilineQuotes: "`",
+			lineComments:    []string{`//`},
+			blockComments:   [][2]string{{`/*`, `*/`}},
+		}
+		syntaxPython = &highlightSyntax{
+			keywords: newWordSet(`and as assert async await break class
+				continue def del elif else except finally for from
+				global if import in is lambda nonlocal not or pass
+				raise return try while with yield`),
+			builtins: newWordSet(`False None True bool dict float int
+				len list print range self set str tuple`),
+			quotes:          `"'`,
+			lineComments:    []string{`#`},
+			isCommentOnWord: true,
+			hasTripleQuote:  true,
+		}
+
+		name string
+	)
+
+	for _, name = range []string{`go`, `golang`} {
+		highlightSyntaxes[name] = syntaxGo
+	}
+	for _, name = range []string{`bash`, `console`, `sh`, `shell`, `zsh`} {
+		highlightSyntaxes[name] = syntaxShell
+	}
+	highlightSyntaxes[`json`] = syntaxJSON
+	for _, name = range []string{`yaml`, `yml`} {
+		highlightSyntaxes[name] = syntaxYAML
+	}
+	highlightSyntaxes[`sql`] = syntaxSQL
+	for _, name = range []string{`javascript`, `js`} {
+		highlightSyntaxes[name] = syntaxJavaScript
+	}
+	for _, name = range []string{`py`, `python`} {
+		highlightSyntaxes[name] = syntaxPython
+	}
+}
+
+// newWordSet create set of words from string separated by white spaces.
+func newWordSet(words string) (set map[string]bool) {
+	var word string
+
+	set = map[string]bool{}
+	for _, word = range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// highlightBuffer contains the highlighted source.
+// Each token is written per line, so the HTML tag does not span multiple
+// lines.
+type highlightBuffer struct {
+	bytes.Buffer
+}
+
+// writeToken write the text wrapped by span with class.
+// If class is empty, the text is written as is.
+func (hb *highlightBuffer) writeToken(class, text string) {
+	var (
+		lines = strings.Split(text, "\n")
+
+		line string
+		x    int
+	)
+	for x, line = range lines {
+		if x > 0 {
+			hb.WriteByte('\n')
+		}
+		if len(line) == 0 {
+			continue
+		}
+		if len(class) == 0 {
+			hb.Write(htmlSubsChar([]byte(line)))
+			continue
+		}
+		hb.WriteString(`<span class="`)
+		hb.WriteString(class)
+		hb.WriteString(`">`)
+		hb.Write(htmlSubsChar([]byte(line)))
+		hb.WriteString(`</span>`)
+	}
+}
+
+// highlightSource highlight the escaped source code based on the language.
+// It will return the content as is if the language is not supported.
+func highlightSource(lang string, content []byte) []byte {
+	var (
+		text = htmlUnescape(string(content))
+		hb   highlightBuffer
+	)
+
+	lang = strings.ToLower(lang)
+	switch lang {
+	case `adoc`, `asciidoc`:
+		hb.highlightAsciidoc(text)
+		return hb.Bytes()
+	}
+
+	var (
+		syntax *highlightSyntax
+		ok     bool
+	)
+	syntax, ok = highlightSyntaxes[lang]
+	if !ok {
+		return content
+	}
+	hb.highlight(syntax, text)
+	return hb.Bytes()
+}
+
+// htmlUnescape revert the special characters substitution.
+func htmlUnescape(text string) string {
+	var replacer = strings.NewReplacer(
+		htmlSymbolLessthan, `<`,
+		htmlSymbolGreaterthan, `>`,
+		htmlSymbolAmpersand, `&`,
+	)
+	return replacer.Replace(text)
+}
+
+func (hb *highlightBuffer) highlight(syntax *highlightSyntax, text string) {
+	var (
+		x     int
+		start int
+		c     byte
+		n     int
+		class string
+	)
+
+	for x < len(text) {
+		c = text[x]
+		start = x
+
+		n = syntax.matchComment(text, x)
+		if n > 0 {
+			x += n
+			hb.writeToken(tokenClassComment, text[start:x])
+			continue
+		}
+
+		if strings.IndexByte(syntax.quotes, c) >= 0 {
+			x += syntax.scanString(text, x)
+			class = tokenClassString
+			if syntax.hasKey && isFollowedByColon(text, x, false) {
+				class = tokenClassKey
+			}
+			hb.writeToken(class, text[start:x])
+			continue
+		}
+
+		if syntax.varPrefix != 0 && c == syntax.varPrefix {
+			x += scanVariable(text, x)
+			hb.writeToken(tokenClassVariable, text[start:x])
+			continue
+		}
+
+		if ascii.IsDigit(c) {
+			for x < len(text) && (ascii.IsAlnum(text[x]) || text[x] == '.' || text[x] == '_') {
+				x++
+			}
+			hb.writeToken(tokenClassNumber, text[start:x])
+			continue
+		}
+
+		if ascii.IsAlpha(c) || c == '_' {
+			for x < len(text) && syntax.isIdentChar(text[x]) {
+				x++
+			}
+			hb.writeToken(syntax.classOfWord(text, start, x), text[start:x])
+			continue
+		}
+
+		x++
+		hb.writeToken(``, text[start:x])
+	}
+}
+
+// matchComment return the length of comment that start at index x, or 0 if
+// there is no comment.
+func (syntax *highlightSyntax) matchComment(text string, x int) int {
+	var (
+		rest = text[x:]
+
+		prefix string
+		pair   [2]string
+		end    int
+	)
+
+	for _, pair = range syntax.blockComments {
+		if !strings.HasPrefix(rest, pair[0]) {
+			continue
+		}
+		end = strings.Index(rest[len(pair[0]):], pair[1])
+		if end < 0 {
+			return len(rest)
+		}
+		return len(pair[0]) + end + len(pair[1])
+	}
+
+	if syntax.isCommentOnWord && x > 0 && !ascii.IsSpace(text[x-1]) {
+		return 0
+	}
+	for _, prefix = range syntax.lineComments {
+		if !strings.HasPrefix(rest, prefix) {
+			continue
+		}
+		end = strings.IndexByte(rest, '\n')
+		if end < 0 {
+			return len(rest)
+		}
+		return end
+	}
+	return 0
+}
+
+// scanString return the length of quoted string that start at index x.
+func (syntax *highlightSyntax) scanString(text string, x int) int {
+	var (
+		quote       = text[x]
+		isMultiline = strings.IndexByte(syntax.multilineQuotes, quote) >= 0
+		start       = x
+	)
+
+	if syntax.hasTripleQuote {
+		var triple = strings.Repeat(string(quote), 3)
+		if strings.HasPrefix(text[x:], triple) {
+			var end = strings.Index(text[x+3:], triple)
+			if end < 0 {
+				return len(text) - x
+			}
+			return 3 + end + 3
+		}
+	}
+
+	for x++; x < len(text); x++ {
+		switch text[x] {
+		case '\\':
+			if quote != '`' {
+				x++
+			}
+		case '\n':
+			if !isMultiline {
+				return x - start
+			}
+		case quote:
+			return x - start + 1
+		}
+	}
+	return x - start
+}
+
+// scanVariable return the length of variable that start at index x, for
+// example "$HOME" or "${HOME}".
+func scanVariable(text string, x int) int {
+	var start = x
+
+	x++
+	if x < len(text) && text[x] == '{' {
+		var end = strings.IndexByte(text[x:], '}')
+		if end >= 0 {
+			return x - start + end + 1
+		}
+	}
+	for x < len(text) && (ascii.IsAlnum(text[x]) || text[x] == '_') {
+		x++
+	}
+	return x - start
+}
+
+func (syntax *highlightSyntax) isIdentChar(c byte) bool {
+	if ascii.IsAlnum(c) || c == '_' {
+		return true
+	}
+	return strings.IndexByte(syntax.identChars, c) >= 0
+}
+
+// classOfWord return the token class for identifier in text[start:end].
+func (syntax *highlightSyntax) classOfWord(text string, start, end int) string {
+	var word = text[start:end]
+
+	if syntax.hasKey && isFollowedByColon(text, end, true) {
+		return tokenClassKey
+	}
+	if syntax.isKeywordFold {
+		word = strings.ToLower(word)
+	}
+	if syntax.keywords[word] {
+		return tokenClassKeyword
+	}
+	if syntax.builtins[word] {
+		return tokenClassBuiltin
+	}
+	return ``
+}
+
+// isFollowedByColon return true if the text at index x, after optional
+// spaces, is ":".
+// If isStrict is true, the ":" must be followed by space or end of text.
+func isFollowedByColon(text string, x int, isStrict bool) bool {
+	for x < len(text) && (text[x] == ' ' || text[x] == '\t') {
+		x++
+	}
+	if x >= len(text) || text[x] != ':' {
+		return false
+	}
+	if !isStrict {
+		return true
+	}
+	x++
+	return x == len(text) || ascii.IsSpace(text[x])
+}
+
+// highlightAsciidoc highlight the AsciiDoc markup per line.
+func (hb *highlightBuffer) highlightAsciidoc(text string) {
+	var (
+		lines = strings.Split(text, "\n")
+
+		line string
+		x    int
+	)
+
+	for x, line = range lines {
+		if x > 0 {
+			hb.WriteByte('\n')
+		}
+		switch {
+		case strings.HasPrefix(line, `//`):
+			hb.writeToken(tokenClassComment, line)
+		case isAsciidocHeading(line):
+			hb.writeToken(tokenClassHeading, line)
+		case isAsciidocDelimiter(line):
+			hb.writeToken(tokenClassMeta, line)
+		case len(line) > 1 && line[0] == '[' && line[len(line)-1] == ']':
+			hb.writeToken(tokenClassMeta, line)
+		case len(line) > 1 && line[0] == ':':
+			var end = strings.IndexByte(line[1:], ':')
+			if end < 0 {
+				hb.writeAsciidocText(line)
+				continue
+			}
+			hb.writeToken(tokenClassKey, line[:end+2])
+			hb.writeAsciidocText(line[end+2:])
+		default:
+			hb.writeAsciidocText(line)
+		}
+	}
+}
+
+// writeAsciidocText write the line with attribute reference "{name}"
+// highlighted as variable.
+func (hb *highlightBuffer) writeAsciidocText(line string) {
+	var start, end int
+
+	for {
+		start = strings.IndexByte(line, '{')
+		if start < 0 {
+			break
+		}
+		end = strings.IndexByte(line[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start + 1
+		hb.writeToken(``, line[:start])
+		hb.writeToken(tokenClassVariable, line[start:end])
+		line = line[end:]
+	}
+	hb.writeToken(``, line)
+}
+
+func isAsciidocHeading(line string) bool {
+	var x int
+	for x < len(line) && line[x] == '=' {
+		x++
+	}
+	return x > 0 && x <= 6 && x < len(line) && line[x] == ' '
+}
+
+func isAsciidocDelimiter(line string) bool {
+	switch line {
+	case `----`, `....`, `====`, `****`, `____`, `++++`, `--`, `|===`, `////`:
+		return true
+	}
+	return false
+}
+
+// isSourceLinenums return true if the source block has option "linenums",
+// or document attribute "source-linenums-option" is set.
+func (el *element) isSourceLinenums(doc *Document) bool {
+	var ok bool
+
+	if libstrings.IsContain(el.options, optNameLinenums) {
+		return true
+	}
+	_, ok = el.Attrs[optNameLinenums]
+	if ok {
+		return true
+	}
+	_, ok = doc.Attributes.Entry[docAttrSourceLinenumsOption]
+	return ok
+}
+
+// sourceLineStart return the line number of the first line in source block
+// from attribute "start", default to 1.
+func (el *element) sourceLineStart() (start int) {
+	var (
+		v   string
+		err error
+		ok  bool
+	)
+
+	v, ok = el.Attrs[attrNameStart]
+	if !ok {
+		return 1
+	}
+	start, err = strconv.Atoi(v)
+	if err != nil {
+		return 1
+	}
+	return start
+}
+
+// htmlHighlightLines wrap the lines that is selected by attribute
+// "highlight" with span class "hll".
+// The line numbers in the attribute are relative to the start.
+func (el *element) htmlHighlightLines(content []byte, start int) []byte {
+	var v, ok = el.Attrs[attrNameHighlight]
+	if !ok {
+		return content
+	}
+
+	var ranges = parseLineRanges(v)
+	if len(ranges) == 0 {
+		return content
+	}
+
+	var (
+		lines = bytes.Split(content, []byte{'\n'})
+		out   bytes.Buffer
+
+		line []byte
+		x    int
+	)
+	for x, line = range lines {
+		if x > 0 {
+			out.WriteByte('\n')
+		}
+		if !isLineInRanges(ranges, start+x) {
+			out.Write(line)
+			continue
+		}
+		fmt.Fprintf(&out, `<span class=%q>%s</span>`,
+			classNameHighlightLine, line)
+	}
+	return out.Bytes()
+}
+
+// htmlLinenums wrap the content inside table with line numbers.
+func htmlLinenums(content []byte, start int) []byte {
+	var (
+		n   = bytes.Count(content, []byte{'\n'}) + 1
+		out bytes.Buffer
+		x   int
+	)
+
+	out.WriteString(`<table class="linenotable"><tbody><tr><td class="linenos"><pre class="lineno">`)
+	for x = 0; x < n; x++ {
+		if x > 0 {
+			out.WriteByte('\n')
+		}
+		out.WriteString(strconv.Itoa(start + x))
+	}
+	out.WriteString(`</pre></td><td class="code"><pre>`)
+	out.Write(content)
+	out.WriteString(`</pre></td></tr></tbody></table>`)
+	return out.Bytes()
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+)
+
+func TestHighlightSource(t *testing.T) {
+	type testCase struct {
+		lang    string
+		content string
+		exp     string
+	}
+
+	var cases = []testCase{{
+		lang:    `json`,
+		content: `{"key": "value", "n":1, "ok": true}`,
+		exp:     `{<span class="tok-key">"key"</span>: <span class="tok-string">"value"</span>, <span class="tok-key">"n"</span>:<span class="tok-number">1</span>, <span class="tok-key">"ok"</span>: <span class="tok-builtin">true</span>}`,
+	}, {
+		lang: `yaml`,
+		content: `name: test # comment
+url: http://x.y`,
+		exp: `<span class="tok-key">name</span>: test <span class="tok-comment"># comment</span>
+<span class="tok-key">url</span>: http://x.y`,
+	}, {
+		lang: `bash`,
+		content: `echo "$HOME" ${PATH} # comment
+ls a#b`,
+		exp: `<span class="tok-builtin">echo</span> <span class="tok-string">"$HOME"</span> <span class="tok-variable">${PATH}</span> <span class="tok-comment"># comment</span>
+ls a#b`,
+	}, {
+		lang:    `sql`,
+		content: `SELECT * FROM t WHERE a = 'x'; -- c`,
+		exp:     `<span class="tok-keyword">SELECT</span> * <span class="tok-keyword">FROM</span> t <span class="tok-keyword">WHERE</span> a = <span class="tok-string">'x'</span>; <span class="tok-comment">-- c</span>`,
+	}, {
+		lang:    `js`,
+		content: `const a = b &lt; 1; /* c */`,
+		exp:     `<span class="tok-keyword">const</span> a = b &lt; <span class="tok-number">1</span>; <span class="tok-comment">/* c */</span>`,
+	}, {
+		lang: `python`,
+		content: `def f():
+    """doc
+    string"""
+    return None`,
+		exp: `<span class="tok-keyword">def</span> f():
+    <span class="tok-string">"""doc</span>
+<span class="tok-string">    string"""</span>
+    <span class="tok-keyword">return</span> <span class="tok-builtin">None</span>`,
+	}, {
+		lang: `asciidoc`,
+		content: `= Title
+:attr: value
+
+Text with {attr}.`,
+		exp: `<span class="tok-heading">= Title</span>
+<span class="tok-key">:attr:</span> value
+
+Text with <span class="tok-variable">{attr}</span>.`,
+	}}
+
+	var (
+		c   testCase
+		got []byte
+	)
+	for _, c = range cases {
+		got = highlightSource(c.lang, []byte(c.content))
+		test.Assert(t, c.lang, c.exp, string(got))
+	}
+}
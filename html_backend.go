@@ -15,6 +15,7 @@ import (
 
 const (
 	classNameArticle      = `article`
+	classNameBook         = `book`
 	classNameHalignCenter = `halign-center`
 	classNameHalignLeft   = `halign-left`
 	classNameHalignRight  = `halign-right`
@@ -62,29 +63,41 @@ const (
 )
 
 // htmlSubs apply the text substitutions to element.raw based on applySubs in
-// the following order: c, q, a, r, m, p.
+// the order of element.subsOrder, or in the following order if its empty:
+// c, q, a, r, m, p.
 // If applySubs is 0, it will return element.raw as is.
 func htmlSubs(doc *Document, el *element) []byte {
 	var (
 		input = el.raw
+		order = el.subsOrder
+		sub   int
 	)
 	if el.applySubs == 0 {
 		return input
 	}
-	if el.applySubs&passSubChar != 0 {
-		input = htmlSubsChar(input)
+	if len(order) == 0 {
+		order = _passSubOrder
 	}
-	if el.applySubs&passSubQuote != 0 {
-		input = htmlSubsQuote(input)
-	}
-	if el.applySubs&passSubAttr != 0 {
-		input = htmlSubsAttr(doc, input)
-	}
-	if el.applySubs&passSubRepl != 0 {
-		input = htmlSubsRepl(input)
-	}
-	if el.applySubs&passSubMacro != 0 {
-		input = htmlSubsMacro(doc, input, el.kind == elKindInlinePass)
+	for _, sub = range order {
+		if el.applySubs&sub == 0 {
+			continue
+		}
+		switch sub {
+		case passSubChar:
+			input = htmlSubsChar(input)
+		case passSubQuote:
+			input = htmlSubsQuote(input)
+		case passSubAttr:
+			var file, lineNum = doc.lineSource(el.lineNum)
+			input, _ = doc.applyAttributeMissing(input, file, lineNum, false)
+			input = htmlSubsAttr(doc, input)
+		case passSubRepl:
+			input = htmlSubsRepl(input)
+		case passSubMacro:
+			input = htmlSubsMacro(doc, input, el.kind == elKindInlinePass)
+		case passSubPostRepl:
+			input = htmlSubsPostRepl(input)
+		}
 	}
 	return input
 }
@@ -261,7 +274,6 @@ func htmlSubsQuote(input []byte) []byte {
 func htmlSubsAttr(doc *Document, input []byte) []byte {
 	var (
 		bb     bytes.Buffer
-		key    string
 		val    string
 		vbytes []byte
 		idx    int
@@ -283,29 +295,12 @@ func htmlSubsAttr(doc *Document, input []byte) []byte {
 			bb.WriteByte(c)
 			continue
 		}
-		vbytes = bytes.TrimSpace(vbytes)
-		vbytes = bytes.ToLower(vbytes)
-
-		key = string(vbytes)
-		val, ok = _attrRef[key]
-		if ok {
-			bb.WriteString(val)
-			x = x + idx + 1
-			continue
-		}
-
-		val, ok = doc.Attributes.Entry[key]
+		val, ok = doc.attrRefValue(string(vbytes))
 		if !ok {
 			bb.WriteByte(c)
 			continue
 		}
 
-		// Add prefix "mailto:" if the ref name start with email, so
-		// it can be parsed by caller as macro link.
-		if key == `email` || strings.HasPrefix(key, `email_`) {
-			val = `mailto:` + val + `[` + val + `]`
-		}
-
 		bb.WriteString(val)
 		x = x + idx + 1
 	}
@@ -574,6 +569,33 @@ func htmlSubsMacro(doc *Document, input []byte, isInlinePass bool) (out []byte)
 	return out
 }
 
+// htmlSubsPostRepl replace the line break, a space followed by "+" at the
+// end of line, with "<br>".
+//
+// Ref: https://docs.asciidoctor.org/asciidoc/latest/subs/post-replacements/
+func htmlSubsPostRepl(input []byte) (out []byte) {
+	var (
+		lines = bytes.Split(input, []byte("\n"))
+
+		line []byte
+		x    int
+	)
+	out = make([]byte, 0, len(input))
+	for x, line = range lines {
+		if x > 0 {
+			out = append(out, '\n')
+		}
+		if x < len(lines)-1 && bytes.HasSuffix(line, []byte(" +")) {
+			line = bytes.TrimRight(line[:len(line)-1], " \t")
+			out = append(out, line...)
+			out = append(out, "<br>"...)
+			continue
+		}
+		out = append(out, line...)
+	}
+	return out
+}
+
 func htmlWriteBlockBegin(el *element, out io.Writer, addClass string) {
 	fmt.Fprint(out, "\n<div")
 
@@ -656,8 +678,9 @@ func htmlWriteBlockExample(doc *Document, el *element, out io.Writer) {
 	htmlWriteBlockBegin(el, out, `exampleblock`)
 	if len(el.rawTitle) > 0 {
 		doc.counterExample++
-		fmt.Fprintf(out, "\n<div class=%q>Example %d. %s</div>",
-			attrValueTitle, doc.counterExample, el.rawTitle)
+		var caption = doc.captionPrefix(el, docAttrExampleCaption,
+			doc.counterExample)
+		htmlWriteCaptionTitle(out, `div`, caption, el.rawTitle)
 	}
 	fmt.Fprintf(out, "\n<div class=%q>", attrValueContent)
 }
@@ -688,30 +711,51 @@ func htmlWriteBlockImage(doc *Document, el *element, out io.Writer) {
 
 	if len(el.rawTitle) > 0 {
 		doc.counterImage++
-		fmt.Fprintf(out, "\n<div class=%q>Figure %d. %s</div>",
-			attrValueTitle, doc.counterImage, el.rawTitle)
+		var caption = doc.captionPrefix(el, docAttrFigureCaption,
+			doc.counterImage)
+		htmlWriteCaptionTitle(out, `div`, caption, el.rawTitle)
 	}
 
 	fmt.Fprint(out, "\n</div>")
 }
 
-func htmlWriteBlockLiteral(el *element, out io.Writer) {
+func htmlWriteBlockLiteral(doc *Document, el *element, out io.Writer) {
 	htmlWriteBlockBegin(el, out, ``)
 
 	var (
-		source string
-		class  string
-		ok     bool
+		content = el.raw
+
+		source   string
+		class    string
+		preClass = `highlight`
+		ok       bool
 	)
 	source, ok = el.Attrs[attrNameSource]
 	if ok {
+		// The content that has been substituted other than the
+		// special characters may contains HTML, so its not
+		// highlighted.
+		if doc.Attributes.Entry[docAttrSourceHighlighter] == sourceHighlighterBuiltin &&
+			el.applySubs == passSubVerbatim {
+			content = highlightSource(source, content)
+			preClass = sourceHighlighterBuiltin + ` ` + preClass
+		}
+		var start = el.sourceLineStart()
+
+		content = el.htmlCallouts(content)
+		content = el.htmlHighlightLines(content, start)
+		if el.isSourceLinenums(doc) {
+			content = htmlLinenums(content, start)
+		}
+
 		class = `language-` + source
-		fmt.Fprint(out, "\n<div class=\"content\">\n<pre class=\"highlight\">")
+		fmt.Fprintf(out, "\n<div class=\"content\">\n<pre class=%q>", preClass)
 		fmt.Fprintf(out, `<code class=%q data-lang=%q>%s</code></pre>`,
-			class, source, el.raw)
+			class, source, content)
 		fmt.Fprint(out, "\n</div>\n</div>")
 	} else {
-		fmt.Fprintf(out, _htmlBlockLiteralContent, el.raw)
+		content = el.htmlCallouts(content)
+		fmt.Fprintf(out, _htmlBlockLiteralContent, content)
 	}
 }
 
@@ -897,6 +941,15 @@ func htmlWriteBody(doc *Document, out *bytes.Buffer) {
 	}
 }
 
+// htmlWriteCaptionTitle write the title of formal block inside the HTML tag,
+// prefixed with its caption if its not empty.
+func htmlWriteCaptionTitle(out io.Writer, tag, caption, title string) {
+	if len(caption) > 0 {
+		title = caption + ` ` + title
+	}
+	fmt.Fprintf(out, "\n<%s class=%q>%s</%s>", tag, attrValueTitle, title, tag)
+}
+
 func htmlWriteFooter(doc *Document, out io.Writer) {
 	var (
 		label string
@@ -909,9 +962,9 @@ func htmlWriteFooter(doc *Document, out io.Writer) {
 <div id="footer-text">`)
 
 	if len(doc.Revision.Number) > 0 {
-		label, ok = doc.Attributes.Entry[docAttrVersionLabel]
-		if ok && len(label) == 0 {
-			label = `Version `
+		label, ok = doc.label(docAttrVersionLabel)
+		if ok {
+			label += ` `
 		} else {
 			label = ` `
 		}
@@ -919,7 +972,7 @@ func htmlWriteFooter(doc *Document, out io.Writer) {
 		fmt.Fprintf(out, "\n%s%s<br>", label, doc.Revision.Number)
 	}
 
-	label, ok = doc.Attributes.Entry[docAttrLastUpdateLabel]
+	label, ok = doc.label(docAttrLastUpdateLabel)
 	if ok {
 		value = doc.Attributes.Entry[docAttrLastUpdateValue]
 		if len(value) != 0 {
@@ -982,6 +1035,34 @@ func htmlWriteFootnoteDefs(doc *Document, out io.Writer) {
 func htmlWriteHeader(doc *Document, out io.Writer) {
 	fmt.Fprint(out, `<div id="header">`)
 
+	var ok bool
+
+	if doc.isBook() {
+		htmlWriteTitlePage(doc, out)
+	} else {
+		_, ok = doc.Attributes.Entry[docAttrShowTitle]
+		if ok {
+			_, ok = doc.Attributes.Entry[docAttrNoTitle]
+			if !ok && doc.Title.el != nil {
+				fmt.Fprint(out, "\n<h1>")
+				doc.Title.el.toHTML(doc, out)
+				fmt.Fprint(out, "</h1>")
+			}
+		}
+		htmlWriteHeaderDetails(doc, out)
+	}
+
+	if doc.tocIsEnabled && (doc.tocPosition == `` ||
+		doc.tocPosition == docAttrValueAuto ||
+		doc.tocPosition == docAttrValueLeft ||
+		doc.tocPosition == docAttrValueRight) {
+		doc.tocHTML(out)
+	}
+	fmt.Fprint(out, "\n</div>")
+}
+
+// htmlWriteHeaderDetails write the authors and revision in the header.
+func htmlWriteHeaderDetails(doc *Document, out io.Writer) {
 	var (
 		haveHeader = doc.haveHeader()
 
@@ -992,16 +1073,6 @@ func htmlWriteHeader(doc *Document, out io.Writer) {
 		ok     bool
 	)
 
-	_, ok = doc.Attributes.Entry[docAttrShowTitle]
-	if ok {
-		_, ok = doc.Attributes.Entry[docAttrNoTitle]
-		if !ok && doc.Title.el != nil {
-			fmt.Fprint(out, "\n<h1>")
-			doc.Title.el.toHTML(doc, out)
-			fmt.Fprint(out, "</h1>")
-		}
-	}
-
 	if haveHeader {
 		fmt.Fprint(out, "\n<div class=\"details\">")
 	}
@@ -1027,9 +1098,9 @@ func htmlWriteHeader(doc *Document, out io.Writer) {
 	}
 
 	if len(doc.Revision.Number) > 0 {
-		prefix, ok = doc.Attributes.Entry[docAttrVersionLabel]
-		if ok && len(prefix) == 0 {
-			prefix = defVersionPrefix
+		prefix, ok = doc.label(docAttrVersionLabel)
+		if ok {
+			prefix = strings.ToLower(prefix) + ` `
 		} else {
 			prefix = ` `
 		}
@@ -1053,14 +1124,21 @@ func htmlWriteHeader(doc *Document, out io.Writer) {
 	if haveHeader {
 		fmt.Fprint(out, "\n</div>")
 	}
+}
 
-	if doc.tocIsEnabled && (doc.tocPosition == `` ||
-		doc.tocPosition == docAttrValueAuto ||
-		doc.tocPosition == docAttrValueLeft ||
-		doc.tocPosition == docAttrValueRight) {
-		doc.tocHTML(out)
+// htmlWriteInlineSpanBegin write the "span" element with optional ID and
+// roles as its class.
+func htmlWriteInlineSpanBegin(el *element, out io.Writer) {
+	var classes = el.htmlClasses()
+
+	fmt.Fprint(out, "<span")
+	if len(el.ID) > 0 {
+		fmt.Fprintf(out, " id=%q", el.ID)
 	}
-	fmt.Fprint(out, "\n</div>")
+	if len(classes) > 0 {
+		fmt.Fprintf(out, " class=%q", classes)
+	}
+	fmt.Fprint(out, ">")
 }
 
 func htmlWriteInlinePass(doc *Document, el *element, out io.Writer) {
@@ -1078,6 +1156,9 @@ func htmlWriteListDescription(el *element, out io.Writer) {
 	case el.isStyleHorizontal():
 		htmlWriteBlockBegin(el, out, `hdlist`)
 		openTag = "\n<table>"
+	case el.isStyleGlossary():
+		htmlWriteBlockBegin(el, out, `dlist glossary`)
+		openTag = "\n<dl>"
 	default:
 		htmlWriteBlockBegin(el, out, `dlist`)
 		openTag = "\n<dl>"
@@ -1097,6 +1178,27 @@ func htmlWriteListDescriptionEnd(el *element, out io.Writer) {
 	}
 }
 
+func htmlWriteListCallout(el *element, out io.Writer) {
+	htmlWriteBlockBegin(el, out, `colist arabic`)
+	fmt.Fprint(out, "\n<table>")
+}
+
+func htmlWriteListCalloutItem(el *element, out io.Writer) {
+	if len(el.ID) != 0 {
+		fmt.Fprintf(out, "\n<tr id=%q>\n<td>", el.ID)
+	} else {
+		fmt.Fprint(out, "\n<tr>\n<td>")
+	}
+	var marker = fmt.Sprintf(`<i class="conum" data-value="%d"></i><b>%d</b>`,
+		el.listItemNumber, el.listItemNumber)
+	if len(el.callouts) != 0 {
+		fmt.Fprintf(out, `<a href="#%s">%s</a>`, el.callouts[0].ref, marker)
+	} else {
+		fmt.Fprint(out, marker)
+	}
+	fmt.Fprint(out, "</td>\n<td>")
+}
+
 func htmlWriteListOrdered(el *element, out io.Writer) {
 	var (
 		class = el.getListOrderedClass()
@@ -1174,7 +1276,9 @@ func htmlWriteSection(doc *Document, el *element, out io.Writer) {
 		fmt.Fprintf(out, `<a class="link" href="#%s">`, el.ID)
 	}
 
-	if el.sectnums != nil && el.level <= doc.sectLevel {
+	if len(el.caption) > 0 {
+		fmt.Fprint(out, el.caption)
+	} else if el.sectnums != nil && el.level <= doc.sectLevel {
 		fmt.Fprint(out, el.sectnums.String())
 	}
 
@@ -1188,6 +1292,28 @@ func htmlWriteSection(doc *Document, el *element, out io.Writer) {
 	if el.kind == elKindSectionL1 {
 		fmt.Fprint(out, "\n<div class=\"sectionbody\">")
 	}
+
+	switch {
+	case el.hasStyle(styleSectionAbstract):
+		fmt.Fprint(out, "\n<div class=\"quoteblock abstract\">\n<blockquote>")
+	case el.hasStyle(styleSectionPartIntroduction):
+		fmt.Fprint(out, "\n<div class=\"openblock partintro\">\n<div class=\"content\">")
+	}
+}
+
+func htmlWriteSectionEnd(doc *Document, el *element, out io.Writer) {
+	switch {
+	case el.hasStyle(styleSectionAbstract):
+		fmt.Fprint(out, "\n</blockquote>\n</div>")
+	case el.hasStyle(styleSectionPartIntroduction):
+		fmt.Fprint(out, "\n</div>\n</div>")
+	case el.hasStyle(styleSectionIndex):
+		doc.htmlWriteIndex(out)
+	}
+	if el.kind == elKindSectionL1 {
+		fmt.Fprint(out, "\n</div>")
+	}
+	fmt.Fprint(out, "\n</div>")
 }
 
 func hmltWriteSectionDiscrete(doc *Document, el *element, out io.Writer) {
@@ -1219,8 +1345,6 @@ func htmlWriteTable(doc *Document, el *element, out io.Writer) {
 		footer *tableRow
 		format *columnFormat
 		style  string
-
-		withTableCaption bool
 	)
 
 	if table == nil {
@@ -1236,22 +1360,10 @@ func htmlWriteTable(doc *Document, el *element, out io.Writer) {
 	fmt.Fprint(out, ">")
 
 	if len(el.rawTitle) > 0 {
-		var (
-			caption string
-			ok      bool
-		)
-
 		doc.counterTable++
-		_, withTableCaption = doc.Attributes.Entry[docAttrTableCaption]
-
-		if withTableCaption {
-			caption, ok = el.Attrs[attrNameCaption]
-			if !ok {
-				caption = fmt.Sprintf("Table %d.", doc.counterTable)
-			}
-		}
-		fmt.Fprintf(out, "\n<caption class=%q>%s %s</caption>",
-			attrValueTitle, caption, el.rawTitle)
+		var caption = doc.captionPrefix(el, docAttrTableCaption,
+			doc.counterTable)
+		htmlWriteCaptionTitle(out, `caption`, caption, el.rawTitle)
 	}
 
 	fmt.Fprint(out, "\n<colgroup>")
@@ -1270,7 +1382,7 @@ func htmlWriteTable(doc *Document, el *element, out io.Writer) {
 	)
 
 	if table.hasHeader {
-		htmlWriteTableHeader(doc, rows[0], out)
+		htmlWriteTableHeader(doc, table, rows[0], out)
 		rows = rows[1:]
 	}
 	if table.hasFooter && len(rows) > 0 {
@@ -1300,7 +1412,7 @@ func htmlWriteTableFooter(doc *Document, table *elementTable, footer *tableRow,
 
 }
 
-func htmlWriteTableHeader(doc *Document, header *tableRow, out io.Writer) {
+func htmlWriteTableHeader(doc *Document, table *elementTable, header *tableRow, out io.Writer) {
 	var (
 		classRow = "tableblock halign-left valign-top"
 
@@ -1311,7 +1423,7 @@ func htmlWriteTableHeader(doc *Document, header *tableRow, out io.Writer) {
 	fmt.Fprint(out, "\n<thead>\n<tr>")
 	for _, cell = range header.cells {
 		fmt.Fprintf(out, "\n<th class=%q>", classRow)
-		cont = parseInlineMarkup(doc, bytes.TrimSpace(cell.content))
+		cont = table.parseCellMarkup(doc, bytes.TrimSpace(cell.content))
 		cont.toHTML(doc, out)
 		fmt.Fprint(out, "</th>")
 	}
@@ -1366,7 +1478,7 @@ func htmlWriteTableRow(doc *Document, table *elementTable, row *tableRow, out io
 					fmt.Fprint(out, "\n")
 				}
 				fmt.Fprintf(out, "<p class=%q>", classNameTableBlock)
-				container = parseInlineMarkup(doc, p)
+				container = table.parseCellMarkup(doc, p)
 				container.toHTML(doc, out)
 				fmt.Fprint(out, "</p>")
 			}
@@ -1406,6 +1518,8 @@ func htmlWriteToC(doc *Document, el *element, out io.Writer, level int) {
 	)
 
 	switch el.kind {
+	case elKindSectionL0:
+		sectClass = "sectlevel0"
 	case elKindSectionL1:
 		sectClass = "sectlevel1"
 	case elKindSectionL2:
@@ -1432,17 +1546,7 @@ func htmlWriteToC(doc *Document, el *element, out io.Writer, level int) {
 			}
 		}
 
-		fmt.Fprintf(out, "\n<li><a href=\"#%s\">", el.ID)
-
-		if el.sectnums != nil {
-			fmt.Fprint(out, el.sectnums.String())
-		}
-
-		doc.isForToC = true
-		el.title.toHTML(doc, out)
-		doc.isForToC = false
-
-		fmt.Fprint(out, "</a>")
+		htmlWriteToCItem(doc, el, out)
 	}
 
 	if el.child != nil {
@@ -1460,6 +1564,44 @@ func htmlWriteToC(doc *Document, el *element, out io.Writer, level int) {
 	}
 }
 
+// htmlWriteToCItem write the link to section el as item in table of
+// contents, without closing the "li" element.
+func htmlWriteToCItem(doc *Document, el *element, out io.Writer) {
+	fmt.Fprintf(out, "\n<li><a href=\"#%s\">", el.ID)
+
+	if len(el.caption) > 0 {
+		fmt.Fprint(out, el.caption)
+	} else if el.sectnums != nil {
+		fmt.Fprint(out, el.sectnums.String())
+	}
+
+	doc.isForToC = true
+	el.title.toHTML(doc, out)
+	doc.isForToC = false
+
+	fmt.Fprint(out, "</a>")
+}
+
+// htmlWriteTextMarkBegin write the highlighted text as "mark" element, or
+// as "span" element if it has ID or roles.
+func htmlWriteTextMarkBegin(doc *Document, el *element, out io.Writer) {
+	switch {
+	case len(el.ID) == 0 && len(el.roles) == 0:
+		fmt.Fprint(out, "<mark>")
+	case !doc.isForToC:
+		htmlWriteInlineSpanBegin(el, out)
+	}
+}
+
+func htmlWriteTextMarkEnd(doc *Document, el *element, out io.Writer) {
+	switch {
+	case len(el.ID) == 0 && len(el.roles) == 0:
+		fmt.Fprint(out, "</mark>")
+	case !doc.isForToC:
+		fmt.Fprint(out, "</span>")
+	}
+}
+
 func htmlWriteURLBegin(el *element, out io.Writer) {
 	fmt.Fprintf(out, "<a href=\"%s\"", el.Attrs[attrNameHref])
 
@@ -6,7 +6,7 @@ package asciidoctor
 // HTML templates for head, meta attributes, and footers.
 const (
 	_htmlBegin = `<!DOCTYPE html>
-<html lang="en">
+<html%s>
 <head>
 <meta charset="UTF-8">
 <meta http-equiv="X-UA-Compatible" content="IE=edge">
@@ -85,6 +85,10 @@ const (
 const (
 	_htmlListDescriptionItemBegin = `
 <dt class="hdlist1">%s</dt>
+<dd>`
+
+	_htmlListDescriptionItemGlossaryBegin = `
+<dt>%s</dt>
 <dd>`
 
 	_htmlListDescriptionItemQandABegin = `
@@ -99,6 +103,21 @@ const (
 <td class="hdlist2">`
 )
 
+//---- Stylesheet for built-in source highlighter.
+
+const _highlightBuiltinCSS = `<style>
+pre.builtin .hll{display:inline-block;min-width:100%;background:#fff8c5}
+pre.builtin .tok-builtin{color:#6f42c1}
+pre.builtin .tok-comment{color:#6a737d;font-style:italic}
+pre.builtin .tok-heading{color:#005cc5;font-weight:bold}
+pre.builtin .tok-key{color:#22863a}
+pre.builtin .tok-keyword{color:#d73a49;font-weight:bold}
+pre.builtin .tok-meta{color:#735c0f}
+pre.builtin .tok-number{color:#005cc5}
+pre.builtin .tok-string{color:#032f62}
+pre.builtin .tok-variable{color:#e36209}
+</style>`
+
 //---- Default stylesheet.
 
 const _defaultCSS = `<link rel="stylesheet" href="https://fonts.googleapis.com/css?family=Open+Sans:300,300italic,400,400italic,600,600italic%7CNoto+Serif:400,400italic,700,700italic%7CDroid+Sans+Mono:400,700">
@@ -490,6 +509,30 @@ p,blockquote,dt,td.content,td.hdlist1,span.alt,summary{font-size:1.0625rem}
 p{margin-bottom:1.25rem}
 .sidebarblock p,.sidebarblock dt,.sidebarblock td.content,p.tableblock{font-size:1em}
 .exampleblock>.content{background:#fffef7;border-color:#e0e0dc;box-shadow:0 1px 4px #e0e0dc}
+html[dir=rtl] ul,html[dir=rtl] ol{margin-left:0;margin-right:1.5em}
+html[dir=rtl] ul li ul,html[dir=rtl] ol li ul,html[dir=rtl] ol li ol{margin-left:0;margin-right:1.25em}
+html[dir=rtl] ol{margin-right:1.75em}
+html[dir=rtl] ul li ol{margin-left:0;margin-right:1.5em}
+html[dir=rtl] ul.no-bullet,html[dir=rtl] ol.no-bullet,html[dir=rtl] ol.unnumbered{margin-right:.625em}
+html[dir=rtl] ul.unstyled,html[dir=rtl] ol.unstyled{margin-right:0}
+html[dir=rtl] ul.checklist>li>p:first-child{margin-left:0;margin-right:-1em}
+html[dir=rtl] dl dd{margin-left:0;margin-right:1.125em}
+html[dir=rtl] blockquote{padding:.5625em 1.1875em 0 1.25em;border-left:0;border-right:1px solid #ddd}
+html[dir=rtl] table thead tr th,html[dir=rtl] table thead tr td,html[dir=rtl] table tfoot tr th,html[dir=rtl] table tfoot tr td{text-align:right}
+html[dir=rtl] .title{text-align:right}
+html[dir=rtl] #header .details{padding-left:0;padding-right:.25em}
+html[dir=rtl] #header .details span:first-child{margin-left:0;margin-right:-.125em}
+html[dir=rtl] .admonitionblock>table td.content{padding-left:1.25em;padding-right:1.125em;border-left:0;border-right:1px solid #dddddf}
+html[dir=rtl] #toc>ul{margin-left:0;margin-right:.125em}
+@media screen and (min-width:768px){html[dir=rtl] body.toc2{padding-left:0;padding-right:15em}
+html[dir=rtl] #toc.toc2{border-right-width:0;border-left:1px solid #e7e7e9;left:auto;right:0}
+html[dir=rtl] #toc.toc2 ul ul{padding-left:0;padding-right:1em}
+html[dir=rtl] #toc.toc2 ul.sectlevel0 ul.sectlevel1{padding-right:0}
+html[dir=rtl] body.toc2.toc-right{padding-left:15em;padding-right:0}
+html[dir=rtl] body.toc2.toc-right #toc.toc2{border-left-width:0;border-right:1px solid #e7e7e9;left:0;right:auto}}
+@media screen and (min-width:1280px){html[dir=rtl] body.toc2{padding-left:0;padding-right:20em}
+html[dir=rtl] #toc.toc2 ul ul{padding-right:1.25em}
+html[dir=rtl] body.toc2.toc-right{padding-left:20em;padding-right:0}}
 .print-only{display:none!important}
 @page{margin:1.25cm .75cm}
 @media print{*{box-shadow:none!important;text-shadow:none!important}
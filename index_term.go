@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// indexTerm contains the index term and the ID of anchor where the term
+// defined in the document.
+type indexTerm struct {
+	id string
+
+	// terms contains the primary, secondary, and tertiary terms, with
+	// HTML special characters escaped.
+	terms []string
+}
+
+// indexEntry is the node of generated index.
+type indexEntry struct {
+	term string
+
+	// ids contains the anchor of each occurrence of the term.
+	ids []string
+
+	// entries contains the secondary or tertiary terms.
+	entries []*indexEntry
+}
+
+// parseIndexTerms parse the raw concealed index term,
+//
+//	INDEX_TERMS = TERM [ "," TERM [ "," TERM ] ]
+//
+//	TERM        = TEXT / DQUOTE TEXT DQUOTE
+//
+// The TERM that is enclosed by double quotes may contains comma.
+func parseIndexTerms(raw []byte) (terms []string) {
+	var (
+		term []byte
+		c    byte
+		inDQ bool
+	)
+	for _, c = range raw {
+		if c == '"' {
+			inDQ = !inDQ
+			continue
+		}
+		if c == ',' && !inDQ {
+			terms = appendIndexTerm(terms, term)
+			term = term[:0]
+			continue
+		}
+		term = append(term, c)
+	}
+	terms = appendIndexTerm(terms, term)
+	if len(terms) > 3 {
+		terms = terms[:3]
+	}
+	return terms
+}
+
+func appendIndexTerm(terms []string, term []byte) []string {
+	term = bytes.TrimSpace(term)
+	if len(term) == 0 {
+		return terms
+	}
+	return append(terms, string(htmlSubsChar(term)))
+}
+
+// registerIndexTerm add the index terms into the document and return the
+// ID of anchor for the terms.
+func (doc *Document) registerIndexTerm(terms []string) (id string) {
+	id = fmt.Sprintf(`_indexterm_%d`, len(doc.indexTerms)+1)
+	doc.indexTerms = append(doc.indexTerms, &indexTerm{
+		id:    id,
+		terms: terms,
+	})
+	return id
+}
+
+// indexEntries build the tree of index entries from all index terms,
+// sorted by term.
+func (doc *Document) indexEntries() (entries []*indexEntry) {
+	var (
+		it    *indexTerm
+		entry *indexEntry
+		term  string
+	)
+	for _, it = range doc.indexTerms {
+		var list = &entries
+		for _, term = range it.terms {
+			entry = findIndexEntry(*list, term)
+			if entry == nil {
+				entry = &indexEntry{
+					term: term,
+				}
+				*list = append(*list, entry)
+			}
+			list = &entry.entries
+		}
+		entry.ids = append(entry.ids, it.id)
+	}
+	sortIndexEntries(entries)
+	return entries
+}
+
+func findIndexEntry(entries []*indexEntry, term string) *indexEntry {
+	var entry *indexEntry
+	for _, entry = range entries {
+		if entry.term == term {
+			return entry
+		}
+	}
+	return nil
+}
+
+func sortIndexEntries(entries []*indexEntry) {
+	sort.SliceStable(entries, func(x, y int) bool {
+		var (
+			a = strings.ToLower(entries[x].term)
+			b = strings.ToLower(entries[y].term)
+		)
+		if a == b {
+			return entries[x].term < entries[y].term
+		}
+		return a < b
+	})
+
+	var entry *indexEntry
+	for _, entry = range entries {
+		sortIndexEntries(entry.entries)
+	}
+}
+
+// indexGroup return the group of term, the upper case of first letter, or
+// "@" if the term does not start with letter.
+func indexGroup(term string) string {
+	var r, _ = utf8.DecodeRuneInString(term)
+	if !unicode.IsLetter(r) {
+		return `@`
+	}
+	return string(unicode.ToUpper(r))
+}
+
+// htmlWriteIndex write the generated index, grouped by the first letter
+// of the primary terms.
+func (doc *Document) htmlWriteIndex(out io.Writer) {
+	var entries = doc.indexEntries()
+	if len(entries) == 0 {
+		return
+	}
+
+	var (
+		entry *indexEntry
+		group string
+		prev  string
+	)
+
+	fmt.Fprint(out, "\n<div class=\"index\">")
+	for _, entry = range entries {
+		group = indexGroup(entry.term)
+		if group != prev {
+			if len(prev) > 0 {
+				fmt.Fprint(out, "\n</ul>\n</div>")
+			}
+			fmt.Fprintf(out, "\n<div class=\"indexgroup\">\n<h3>%s</h3>\n<ul>", group)
+			prev = group
+		}
+		htmlWriteIndexEntry(entry, out)
+	}
+	fmt.Fprint(out, "\n</ul>\n</div>\n</div>")
+}
+
+func htmlWriteIndexEntry(entry *indexEntry, out io.Writer) {
+	var (
+		id  string
+		sub *indexEntry
+		x   int
+	)
+
+	fmt.Fprintf(out, "\n<li>%s", entry.term)
+	for x, id = range entry.ids {
+		if x > 0 {
+			fmt.Fprint(out, `,`)
+		}
+		fmt.Fprintf(out, ` <a href="#%s">%d</a>`, id, x+1)
+	}
+	if len(entry.entries) > 0 {
+		fmt.Fprint(out, "\n<ul>")
+		for _, sub = range entry.entries {
+			htmlWriteIndexEntry(sub, out)
+		}
+		fmt.Fprint(out, "\n</ul>\n")
+	}
+	fmt.Fprint(out, `</li>`)
+}
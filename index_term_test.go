@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+)
+
+func TestParseIndexTerms(t *testing.T) {
+	type testCase struct {
+		raw string
+		exp []string
+	}
+
+	var cases = []testCase{{
+		raw: `primary`,
+		exp: []string{`primary`},
+	}, {
+		raw: ` primary , secondary,tertiary `,
+		exp: []string{`primary`, `secondary`, `tertiary`},
+	}, {
+		raw: `"a, b", <c>`,
+		exp: []string{`a, b`, `&lt;c&gt;`},
+	}, {
+		raw: `a, , b, c, d`,
+		exp: []string{`a`, `b`, `c`},
+	}, {
+		raw: ` , `,
+	}}
+
+	var (
+		c   testCase
+		got []string
+	)
+	for _, c = range cases {
+		got = parseIndexTerms([]byte(c.raw))
+		test.Assert(t, c.raw, c.exp, got)
+	}
+}
@@ -5,6 +5,7 @@ package asciidoctor
 
 import (
 	"bytes"
+	"strings"
 
 	"git.sr.ht/~shulhan/pakakeh.go/lib/ascii"
 )
@@ -20,6 +21,20 @@ type inlineParser struct {
 
 	content []byte
 
+	// subs contains the substitutions applied to content, default to
+	// passSubNormal.
+	subs int
+
+	// isHardbreaks set to true to convert each line feed into line
+	// break.
+	// Its default value is true if the document attribute
+	// "hardbreaks-option" is set.
+	isHardbreaks bool
+
+	// lineNum is the line number where the content start, or zero if
+	// its unknown.
+	lineNum int
+
 	x      int
 	prev   byte
 	c      byte
@@ -37,9 +52,17 @@ func newInlineParser(doc *Document, content []byte) (pi *inlineParser) {
 		content: content,
 		doc:     doc,
 		state:   &inlineParserState{},
+		subs:    passSubNormal,
 	}
 	pi.current = pi.container
 
+	var ok bool
+	_, ok = doc.Attributes.Entry[docAttrHardbreaksOption]
+	if !ok {
+		_, ok = doc.Attributes.Entry[docAttrHardbreaks]
+	}
+	pi.isHardbreaks = ok
+
 	return pi
 }
 
@@ -62,7 +85,7 @@ func (pi *inlineParser) do() {
 			pi.nextcc = pi.content[pi.x+2]
 		}
 
-		if pi.c == '\\' {
+		if pi.c == '\\' && pi.hasSubs(passSubQuote|passSubAttr|passSubRepl|passSubMacro) {
 			if pi.isEscaped {
 				pi.escape()
 				pi.prev = 0
@@ -79,7 +102,7 @@ func (pi *inlineParser) do() {
 				pi.escape()
 				continue
 			}
-			if pi.nextc == '+' {
+			if pi.nextc == '+' && pi.hasSubs(passSubMacro) {
 				if pi.x+2 < len(pi.content) && pi.content[pi.x+2] == '+' {
 					if pi.parsePassthroughTriple() {
 						continue
@@ -89,17 +112,25 @@ func (pi *inlineParser) do() {
 					continue
 				}
 			}
-			if pi.prev == ' ' && pi.nextc == '\n' {
+			if pi.prev == ' ' && pi.nextc == '\n' && pi.hasSubs(passSubPostRepl) {
 				pi.current.backTrimSpace()
 				pi.current.WriteString("<br>\n")
 				pi.x += 2
 				pi.prev = 0
 				continue
 			}
-			if pi.parsePassthrough() {
+			if pi.hasSubs(passSubMacro) && pi.parsePassthrough() {
+				continue
+			}
+		} else if pi.c == '\n' && pi.isHardbreaks && pi.hasSubs(passSubPostRepl) {
+			if pi.x+1 < len(pi.content) {
+				pi.current.backTrimSpace()
+				pi.current.WriteString("<br>\n")
+				pi.x++
+				pi.prev = pi.c
 				continue
 			}
-		} else if pi.c == ':' {
+		} else if pi.c == ':' && pi.hasSubs(passSubMacro) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -109,7 +140,7 @@ func (pi *inlineParser) do() {
 					continue
 				}
 			}
-		} else if pi.c == '~' {
+		} else if pi.c == '~' && pi.hasSubs(passSubQuote) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -117,7 +148,7 @@ func (pi *inlineParser) do() {
 			if pi.parseSubscript() {
 				continue
 			}
-		} else if pi.c == '^' {
+		} else if pi.c == '^' && pi.hasSubs(passSubQuote) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -125,7 +156,7 @@ func (pi *inlineParser) do() {
 			if pi.parseSuperscript() {
 				continue
 			}
-		} else if pi.c == '"' {
+		} else if pi.c == '"' && pi.hasSubs(passSubQuote) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -141,19 +172,19 @@ func (pi *inlineParser) do() {
 				pi.escape()
 				continue
 			}
-			if pi.nextc == '`' {
+			if pi.nextc == '`' && pi.hasSubs(passSubQuote) {
 				ok = pi.parseQuoteBegin([]byte("`'"), elKindSymbolQuoteSingleBegin)
 				if ok {
 					continue
 				}
 			}
-			if ascii.IsAlpha(pi.prev) {
+			if ascii.IsAlpha(pi.prev) && pi.hasSubs(passSubRepl) {
 				pi.current.WriteString(htmlSymbolApostrophe)
 				pi.x++
 				pi.prev = pi.c
 				continue
 			}
-		} else if pi.c == '*' {
+		} else if pi.c == '*' && pi.hasSubs(passSubQuote) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -170,7 +201,7 @@ func (pi *inlineParser) do() {
 			if pi.parseFormat(elKindTextBold, styleTextBold) {
 				continue
 			}
-		} else if pi.c == '_' {
+		} else if pi.c == '_' && pi.hasSubs(passSubQuote) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -187,7 +218,7 @@ func (pi *inlineParser) do() {
 			if pi.parseFormat(elKindTextItalic, styleTextItalic) {
 				continue
 			}
-		} else if pi.c == '`' {
+		} else if pi.c == '`' && pi.hasSubs(passSubQuote) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -225,16 +256,16 @@ func (pi *inlineParser) do() {
 				pi.escape()
 				continue
 			}
-			if pi.nextc == '[' {
+			if pi.nextc == '[' && pi.hasSubs(passSubMacro) {
 				if pi.parseInlineID() {
 					continue
 				}
-			} else if pi.nextc == '#' {
+			} else if (pi.nextc == '#' || pi.nextc == '.') && pi.hasSubs(passSubQuote) {
 				if pi.parseInlineIDShort() {
 					continue
 				}
 			}
-		} else if pi.c == '#' {
+		} else if pi.c == '#' && pi.hasSubs(passSubQuote) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -246,32 +277,52 @@ func (pi *inlineParser) do() {
 				pi.prev = 0
 				continue
 			}
+			// The "[#" is the beginning of inline attributes.
+			if pi.prev != '[' {
+				if pi.nextc == '#' {
+					if pi.parseFormatUnconstrained(
+						[]byte(`##`),
+						elKindUnconstrainedMark,
+						elKindTextMark,
+						styleTextMark) {
+						continue
+					}
+				}
+				if pi.parseFormat(elKindTextMark, styleTextMark) {
+					continue
+				}
+			}
 		} else if pi.c == '<' {
 			if pi.isEscaped {
 				pi.escape()
 				continue
 			}
 
-			if pi.nextc == '<' {
+			if pi.nextc == '<' && pi.hasSubs(passSubMacro) {
 				if pi.parseCrossRef() {
 					continue
 				}
-			} else if pi.nextc == '-' {
-				pi.current.WriteString(htmlSymbolSingleLeftArrow)
-				pi.x += 2
-				pi.prev = pi.nextc
-				continue
-			} else if pi.nextc == '=' {
-				pi.current.WriteString(htmlSymbolDoubleLeftArrow)
-				pi.x += 2
-				pi.prev = pi.nextc
+			} else if pi.hasSubs(passSubRepl) {
+				if pi.nextc == '-' {
+					pi.current.WriteString(htmlSymbolSingleLeftArrow)
+					pi.x += 2
+					pi.prev = pi.nextc
+					continue
+				}
+				if pi.nextc == '=' {
+					pi.current.WriteString(htmlSymbolDoubleLeftArrow)
+					pi.x += 2
+					pi.prev = pi.nextc
+					continue
+				}
+			}
+			if pi.hasSubs(passSubChar) {
+				pi.current.WriteString(htmlSymbolLessthan)
+				pi.x++
+				pi.prev = pi.c
 				continue
 			}
-			pi.current.WriteString(htmlSymbolLessthan)
-			pi.x++
-			pi.prev = pi.c
-			continue
-		} else if pi.c == '>' {
+		} else if pi.c == '>' && pi.hasSubs(passSubChar) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -280,14 +331,14 @@ func (pi *inlineParser) do() {
 			pi.x++
 			pi.prev = pi.c
 			continue
-		} else if pi.c == '&' {
+		} else if pi.c == '&' && pi.hasSubs(passSubChar) {
 			if ascii.IsSpace(pi.prev) && ascii.IsSpace(pi.nextc) {
 				pi.current.WriteString(htmlSymbolAmpersand)
 				pi.x += 2
 				pi.prev = pi.nextc
 				continue
 			}
-		} else if pi.c == '{' {
+		} else if pi.c == '{' && pi.hasSubs(passSubAttr) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -300,7 +351,7 @@ func (pi *inlineParser) do() {
 				pi.prev = 0
 				continue
 			}
-		} else if pi.c == '-' {
+		} else if pi.c == '-' && pi.hasSubs(passSubRepl) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -326,7 +377,7 @@ func (pi *inlineParser) do() {
 				pi.prev = pi.nextc
 				continue
 			}
-		} else if pi.c == '=' {
+		} else if pi.c == '=' && pi.hasSubs(passSubRepl) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -337,7 +388,7 @@ func (pi *inlineParser) do() {
 				pi.prev = pi.nextc
 				continue
 			}
-		} else if pi.c == '.' {
+		} else if pi.c == '.' && pi.hasSubs(passSubRepl) {
 			if pi.isEscaped {
 				pi.escape()
 				continue
@@ -354,6 +405,17 @@ func (pi *inlineParser) do() {
 				pi.escape()
 				continue
 			}
+			if pi.nextc == '(' && pi.hasSubs(passSubMacro) {
+				if pi.parseIndexTerm() {
+					continue
+				}
+			}
+			if !pi.hasSubs(passSubRepl) {
+				pi.current.WriteByte(pi.c)
+				pi.x++
+				pi.prev = pi.c
+				continue
+			}
 			var isReplaced bool
 			vbytes, _ = indexByteUnescape(pi.content[pi.x+1:], ')')
 			if len(vbytes) == 1 {
@@ -390,6 +452,11 @@ func (pi *inlineParser) do() {
 	pi.container.removeLastIfEmpty()
 }
 
+// hasSubs return true if one of the substitutions in subs is applied.
+func (pi *inlineParser) hasSubs(subs int) bool {
+	return pi.subs&subs != 0
+}
+
 func (pi *inlineParser) escape() {
 	pi.isEscaped = false
 	pi.current.WriteByte(pi.c)
@@ -434,6 +501,10 @@ func (pi *inlineParser) parseCrossRef() bool {
 		raw:  []byte(label),
 	}
 	pi.current.addChild(elCrossRef)
+	pi.doc.xrefs = append(pi.doc.xrefs, reference{
+		id:      href,
+		lineNum: pi.lineNum,
+	})
 	el = &element{
 		kind: elKindText,
 	}
@@ -486,50 +557,162 @@ func (pi *inlineParser) parseInlineID() bool {
 	return true
 }
 
-// parseInlineIDShort parse the ID and optional label between "[#", "]#", and
-// "#".
+// parseIndexTerm parse the visible index term "((" TERM "))" or the
+// concealed index terms "(((" TERMS ")))".
+// The visible term is kept in the text.
+func (pi *inlineParser) parseIndexTerm() bool {
+	var (
+		isConcealed = pi.nextcc == '('
+		token       = []byte(`))`)
+
+		el      *element
+		visible *element
+		raw     []byte
+		terms   []string
+		idx     int
+	)
+
+	if isConcealed {
+		token = []byte(`)))`)
+	}
+
+	raw, idx = indexUnescape(pi.content[pi.x+len(token):], token)
+	if idx < 0 {
+		return false
+	}
+	if isConcealed {
+		terms = parseIndexTerms(raw)
+	} else {
+		visible = parseInlineMarkup(pi.doc, raw)
+		var term = strings.TrimSpace(visible.toText())
+		if len(term) > 0 {
+			terms = []string{term}
+		}
+	}
+	if len(terms) == 0 {
+		return false
+	}
+
+	el = &element{
+		elementAttribute: elementAttribute{
+			ID: pi.doc.registerIndexTerm(terms),
+		},
+		kind: elKindIndexTerm,
+	}
+	el.addChild(visible)
+	pi.current.addChild(el)
+	el = &element{
+		kind: elKindText,
+	}
+	pi.current.addChild(el)
+	pi.current = el
+	pi.x += len(token) + idx + len(token)
+	pi.prev = 0
+	return true
+}
+
+// parseInlineIDShort parse the text with inline attributes,
+//
+//	"[" INLINE_ATTRS "]#" TEXT "#"
+//
+//	"[" INLINE_ATTRS "]##" TEXT "##"
+//
+//	INLINE_ATTRS = 1*( "#" REF_ID / "." ROLE )
 func (pi *inlineParser) parseInlineIDShort() bool {
 	var (
-		raw = pi.content[pi.x+2:]
+		raw = pi.content[pi.x+1:]
 
-		el       *element
-		stringID string
-		id       []byte
-		idx      int
+		el    *element
+		attrs []byte
+		idx   int
 	)
 
 	// Check if we have term at the end.
-	id, idx = indexUnescape(raw, []byte(`]#`))
+	attrs, idx = indexUnescape(raw, []byte(`]#`))
 	if idx < 0 {
 		return false
 	}
 
-	if !isValidID(id) {
+	var (
+		id, roles, ok = parseInlineAttrs(attrs)
+		kind          = elKindInlineIDShort
+	)
+	if !ok {
 		return false
 	}
 
-	// Check if we have "#"
-	_, idx = indexByteUnescape(raw[idx+2:], '#')
+	raw = raw[idx+2:]
+	if len(raw) > 0 && raw[0] == '#' {
+		kind = elKindUnconstrainedMark
+		_, idx = indexUnescape(raw[1:], []byte(`##`))
+	} else {
+		_, idx = indexByteUnescape(raw, '#')
+	}
 	if idx < 0 {
 		return false
 	}
 
-	stringID = pi.doc.registerAnchor(string(id), ``)
-
 	el = &element{
 		elementAttribute: elementAttribute{
-			ID: stringID,
+			roles: roles,
 		},
-		kind: elKindInlineIDShort,
+		kind: kind,
+	}
+	if len(id) > 0 {
+		el.ID = pi.doc.registerAnchor(id, ``)
+	}
+	pi.state.push(kind)
+	if kind == elKindInlineIDShort && len(roles) == 0 {
+		pi.current.backTrimSpace()
 	}
-	pi.state.push(elKindInlineIDShort)
-	pi.current.backTrimSpace()
 	pi.current.addChild(el)
 	pi.current = el
-	pi.x += 2 + len(id) + 2
+	pi.x += 1 + len(attrs) + 2
+	if kind == elKindUnconstrainedMark {
+		pi.x++
+	}
+	pi.prev = 0
 	return true
 }
 
+// parseInlineAttrs parse the ID and roles in the inline attributes, for
+// example "#id.role1.role2".
+// It will return false if the attributes is empty or contains invalid ID
+// or role.
+func parseInlineAttrs(attrs []byte) (id string, roles []string, ok bool) {
+	if len(attrs) == 0 {
+		return ``, nil, false
+	}
+
+	var (
+		start = 0
+		x     int
+	)
+	for x = 1; x <= len(attrs); x++ {
+		if x < len(attrs) && attrs[x] != '#' && attrs[x] != '.' {
+			continue
+		}
+
+		var name = attrs[start+1 : x]
+		if !isValidID(name) || len(name) == 0 {
+			return ``, nil, false
+		}
+		switch attrs[start] {
+		case '#':
+			if len(id) > 0 {
+				return ``, nil, false
+			}
+			id = string(name)
+		case '.':
+			roles = append(roles, string(name))
+		default:
+			return ``, nil, false
+		}
+		start = x
+	}
+	return id, roles, true
+}
+
 // parseQuoteBegin check if the double quote curve ("`) is valid (does not
 // followed by space) and has an end (`").
 func (pi *inlineParser) parseQuoteBegin(quoteEnd []byte, kind int) bool {
@@ -689,6 +872,7 @@ func (pi *inlineParser) parseMacro() bool {
 		el   *element
 		name string
 		n    int
+		ok   bool
 	)
 
 	name = parseMacroName(pi.current.raw)
@@ -728,6 +912,37 @@ func (pi *inlineParser) parseMacro() bool {
 		}
 		pi.x += n
 		pi.prev = 0
+
+	case macroAsciimath, macroLatexmath, macroStem:
+		_, ok = pi.doc.Attributes.Entry[docAttrStem]
+		if !ok {
+			return false
+		}
+		el, n = parseMacroStem(pi.doc, name, pi.content[pi.x+1:])
+		if el == nil {
+			return false
+		}
+		pi.x += n
+		pi.prev = 0
+
+	case macroBtn, macroKbd, macroMenu:
+		_, ok = pi.doc.Attributes.Entry[docAttrExperimental]
+		if !ok {
+			return false
+		}
+		switch name {
+		case macroBtn:
+			el, n = parseMacroBtn(pi.content[pi.x+1:])
+		case macroKbd:
+			el, n = parseMacroKbd(pi.content[pi.x+1:])
+		default:
+			el, n = parseMacroMenu(pi.content[pi.x+1:])
+		}
+		if el == nil {
+			return false
+		}
+		pi.x += n
+		pi.prev = 0
 	}
 
 	pi.current.raw = pi.current.raw[:len(pi.current.raw)-len(name)]
@@ -975,8 +1190,8 @@ func parseURL(doc *Document, scheme string, content []byte) (el *element, n int)
 	var attr = content[x : x+idx+1]
 	el.style = styleLink
 	el.parseElementAttribute(attr)
-	if len(el.Attrs) == 0 {
-		// empty "[]"
+	if len(el.rawStyle) == 0 {
+		// Empty "[]" or no link text, use the URI as text.
 		el.raw = uri
 		return el, n
 	}
@@ -1022,6 +1237,10 @@ func (pi *inlineParser) terminate(kind int, style int64) {
 			el.style = styleTextMono
 			stateTmp.push(pi.state.pop())
 		}
+		if el.kind == elKindTextMark && el.style == 0 {
+			el.style = styleTextMark
+			stateTmp.push(pi.state.pop())
+		}
 		el = el.parent
 	}
 	if el.parent != nil {
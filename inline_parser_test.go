@@ -145,6 +145,9 @@ func TestInlineParser_macro_pass(t *testing.T) {
 			`testdata/inline_parser/macro_pass_a_test.txt`,
 			`testdata/inline_parser/macro_pass_r_test.txt`,
 			`testdata/inline_parser/macro_pass_m_test.txt`,
+			`testdata/inline_parser/macro_pass_p_test.txt`,
+			`testdata/inline_parser/macro_pass_multi_test.txt`,
+			`testdata/inline_parser/macro_pass_order_test.txt`,
 		}
 
 		testFile   string
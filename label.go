@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defLang define the default language for generated labels.
+const defLang = `en`
+
+// _labels contains the built-in translations of generated labels, for
+// example caption of figure and admonition, grouped by the value of
+// document attribute "lang".
+var _labels = map[string]map[string]string{
+	`ar`: {
+		docAttrAppendixCaption:  `ملحق`,
+		docAttrCautionCaption:   `تنبيه`,
+		docAttrChapterSignifier: `فصل`,
+		docAttrExampleCaption:   `مثال`,
+		docAttrFigureCaption:    `الشكل`,
+		docAttrImportantCaption: `مهم`,
+		docAttrLastUpdateLabel:  `آخر تحديث`,
+		docAttrNoteCaption:      `ملاحظة`,
+		docAttrPartSignifier:    `قسم`,
+		docAttrTOCTitle:         `فهرس المحتويات`,
+		docAttrTableCaption:     `جدول`,
+		docAttrTipCaption:       `تلميح`,
+		docAttrVersionLabel:     `نسخة`,
+		docAttrWarningCaption:   `تحذير`,
+	},
+	`de`: {
+		docAttrAppendixCaption:  `Anhang`,
+		docAttrCautionCaption:   `Achtung`,
+		docAttrChapterSignifier: `Kapitel`,
+		docAttrExampleCaption:   `Beispiel`,
+		docAttrFigureCaption:    `Abbildung`,
+		docAttrImportantCaption: `Wichtig`,
+		docAttrLastUpdateLabel:  `Zuletzt aktualisiert`,
+		docAttrNoteCaption:      `Anmerkung`,
+		docAttrPartSignifier:    `Teil`,
+		docAttrTOCTitle:         `Inhaltsverzeichnis`,
+		docAttrTableCaption:     `Tabelle`,
+		docAttrTipCaption:       `Hinweis`,
+		docAttrVersionLabel:     `Version`,
+		docAttrWarningCaption:   `Warnung`,
+	},
+	`en`: {
+		docAttrAppendixCaption:  `Appendix`,
+		docAttrCautionCaption:   `Caution`,
+		docAttrChapterSignifier: `Chapter`,
+		docAttrExampleCaption:   `Example`,
+		docAttrFigureCaption:    `Figure`,
+		docAttrImportantCaption: `Important`,
+		docAttrLastUpdateLabel:  `Last updated`,
+		docAttrNoteCaption:      `Note`,
+		docAttrPartSignifier:    `Part`,
+		docAttrTOCTitle:         `Table of Contents`,
+		docAttrTableCaption:     `Table`,
+		docAttrTipCaption:       `Tip`,
+		docAttrVersionLabel:     `Version`,
+		docAttrWarningCaption:   `Warning`,
+	},
+	`es`: {
+		docAttrAppendixCaption:  `Apéndice`,
+		docAttrCautionCaption:   `Precaución`,
+		docAttrChapterSignifier: `Capítulo`,
+		docAttrExampleCaption:   `Ejemplo`,
+		docAttrFigureCaption:    `Figura`,
+		docAttrImportantCaption: `Importante`,
+		docAttrLastUpdateLabel:  `Última actualización`,
+		docAttrNoteCaption:      `Nota`,
+		docAttrPartSignifier:    `Parte`,
+		docAttrTOCTitle:         `Tabla de Contenido`,
+		docAttrTableCaption:     `Tabla`,
+		docAttrTipCaption:       `Sugerencia`,
+		docAttrVersionLabel:     `Versión`,
+		docAttrWarningCaption:   `Aviso`,
+	},
+	`fr`: {
+		docAttrAppendixCaption:  `Annexe`,
+		docAttrCautionCaption:   `Avertissement`,
+		docAttrChapterSignifier: `Chapitre`,
+		docAttrExampleCaption:   `Exemple`,
+		docAttrFigureCaption:    `Figure`,
+		docAttrImportantCaption: `Important`,
+		docAttrLastUpdateLabel:  `Dernière mise à jour`,
+		docAttrNoteCaption:      `Note`,
+		docAttrPartSignifier:    `Partie`,
+		docAttrTOCTitle:         `Table des matières`,
+		docAttrTableCaption:     `Tableau`,
+		docAttrTipCaption:       `Astuce`,
+		docAttrVersionLabel:     `Version`,
+		docAttrWarningCaption:   `Attention`,
+	},
+	`id`: {
+		docAttrAppendixCaption:  `Lampiran`,
+		docAttrCautionCaption:   `Perhatian`,
+		docAttrChapterSignifier: `Bab`,
+		docAttrExampleCaption:   `Contoh`,
+		docAttrFigureCaption:    `Gambar`,
+		docAttrImportantCaption: `Penting`,
+		docAttrLastUpdateLabel:  `Pembaruan terakhir`,
+		docAttrNoteCaption:      `Catatan`,
+		docAttrPartSignifier:    `Bagian`,
+		docAttrTOCTitle:         `Daftar Isi`,
+		docAttrTableCaption:     `Tabel`,
+		docAttrTipCaption:       `Tips`,
+		docAttrVersionLabel:     `Versi`,
+		docAttrWarningCaption:   `Peringatan`,
+	},
+}
+
+// labelsByLang return the built-in labels for lang, for example "de" or
+// "de-DE".
+// It will return nil if no translations found.
+func labelsByLang(lang string) map[string]string {
+	lang = strings.ToLower(lang)
+	var labels, ok = _labels[lang]
+	if ok {
+		return labels
+	}
+	var x = strings.IndexAny(lang, `-_`)
+	if x > 0 {
+		return _labels[lang[:x]]
+	}
+	return nil
+}
+
+// setLang replace the value of labels with its translation in lang.
+// The label that has been set or unset in the document is not changed.
+func (doc *Document) setLang(lang string) {
+	var labels = labelsByLang(lang)
+	if labels == nil {
+		return
+	}
+	var key, val string
+	var ok bool
+	for key, val = range labels {
+		_, ok = doc.userLabels[key]
+		if ok {
+			continue
+		}
+		_, ok = doc.Attributes.Entry[key]
+		if ok {
+			doc.Attributes.Entry[key] = val
+		}
+	}
+}
+
+// label return the value of document attribute key.
+// If the attribute is set with empty value, it return the built-in label
+// based on the document "lang".
+// The ok is false if the attribute has been unset.
+func (doc *Document) label(key string) (val string, ok bool) {
+	val, ok = doc.Attributes.Entry[key]
+	if ok && len(val) == 0 {
+		val = doc.labelDefault(key)
+	}
+	return val, ok
+}
+
+// labelDefault return the built-in label for key based on the document
+// "lang", or in English if the translation does not exist.
+func (doc *Document) labelDefault(key string) string {
+	var (
+		labels = labelsByLang(doc.Attributes.Entry[docAttrLang])
+		val    string
+		ok     bool
+	)
+	val, ok = labels[key]
+	if ok {
+		return val
+	}
+	return _labels[defLang][key]
+}
+
+// admonitionLabel return the label for admonition name, for example "Note"
+// for "NOTE", using the document attribute "<name>-caption".
+func (doc *Document) admonitionLabel(admName string) string {
+	var (
+		key     = strings.ToLower(admName) + `-caption`
+		val, ok = doc.label(key)
+	)
+	if !ok {
+		val = doc.labelDefault(key)
+	}
+	if len(val) == 0 {
+		return strings.ToUpper(admName)
+	}
+	return val
+}
+
+// captionPrefix return the caption for formal block with the document
+// attribute key and its number, for example "Figure 1.".
+// The block attribute "caption" replace the generated caption.
+// It will return empty string if the attribute key has been unset.
+func (doc *Document) captionPrefix(el *element, key string, number int) string {
+	var caption, ok = el.Attrs[attrNameCaption]
+	if ok {
+		return caption
+	}
+	caption, ok = doc.label(key)
+	if !ok {
+		return ``
+	}
+	return fmt.Sprintf(`%s %d.`, caption, number)
+}
@@ -11,28 +11,40 @@ import (
 
 // List of macro names.
 const (
-	macroFTP      = `ftp`
-	macroFootnote = `footnote`
-	macroHTTP     = `http`
-	macroHTTPS    = `https`
-	macroIRC      = `irc`
-	macroImage    = `image`
-	macroLink     = `link`
-	macroMailto   = `mailto`
-	macroPass     = `pass`
+	macroAsciimath = `asciimath`
+	macroBtn       = `btn`
+	macroFTP       = `ftp`
+	macroFootnote  = `footnote`
+	macroHTTP      = `http`
+	macroHTTPS     = `https`
+	macroIRC       = `irc`
+	macroImage     = `image`
+	macroKbd       = `kbd`
+	macroLatexmath = `latexmath`
+	macroLink      = `link`
+	macroMailto    = `mailto`
+	macroMenu      = `menu`
+	macroPass      = `pass`
+	macroStem      = `stem`
 )
 
 var (
 	_macroKind = map[string]int{
-		macroFTP:      elKindURL,
-		macroFootnote: elKindFootnote,
-		macroHTTP:     elKindURL,
-		macroHTTPS:    elKindURL,
-		macroIRC:      elKindURL,
-		macroImage:    elKindInlineImage,
-		macroLink:     elKindURL,
-		macroMailto:   elKindURL,
-		macroPass:     elKindText,
+		macroAsciimath: elKindInlineStem,
+		macroBtn:       elKindInlineButton,
+		macroFTP:       elKindURL,
+		macroFootnote:  elKindFootnote,
+		macroHTTP:      elKindURL,
+		macroHTTPS:     elKindURL,
+		macroIRC:       elKindURL,
+		macroImage:     elKindInlineImage,
+		macroKbd:       elKindInlineKbd,
+		macroLatexmath: elKindInlineStem,
+		macroLink:      elKindURL,
+		macroMailto:    elKindURL,
+		macroMenu:      elKindInlineMenu,
+		macroPass:      elKindText,
+		macroStem:      elKindInlineStem,
 	}
 )
 
@@ -169,6 +181,8 @@ func parseMacroFootnote(doc *Document, text []byte) (el *element, n int) {
 //	SUB      = SUB_KIND *("," SUB_KIND)
 //
 //	SUB_KIND = "c" / "q" / "a" / "r" / "m" / "p" / "n" / "v"
+//
+// The substitutions are applied in the order of SUB_KIND.
 func parseMacroPass(text []byte) (el *element, n int) {
 	var (
 		x int
@@ -194,22 +208,23 @@ func parseMacroPass(text []byte) (el *element, n int) {
 			return nil, 0
 		}
 		switch c {
-		case 'c':
-			el.applySubs |= passSubChar
+		case 'c', 'v':
+			el.addSubs(passSubChar)
 		case 'q':
-			el.applySubs |= passSubQuote
+			el.addSubs(passSubQuote)
 		case 'a':
-			el.applySubs |= passSubAttr
+			el.addSubs(passSubAttr)
 		case 'r':
-			el.applySubs |= passSubRepl
+			el.addSubs(passSubRepl)
 		case 'm':
-			el.applySubs |= passSubMacro
+			el.addSubs(passSubMacro)
 		case 'p':
-			el.applySubs |= passSubPostRepl
+			el.addSubs(passSubPostRepl)
 		case 'n':
-			el.applySubs |= passSubNormal
-		case 'v':
-			el.applySubs |= passSubChar
+			var sub int
+			for _, sub = range _passSubOrder {
+				el.addSubs(sub)
+			}
 		}
 	}
 	if c != '[' {
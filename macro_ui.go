@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const htmlMenuCaret = `&#160;<b class="caret">&#8250;</b> `
+
+// parseMacroBtn parse the button macro,
+//
+//	"btn:[" LABEL "]"
+func parseMacroBtn(text []byte) (el *element, n int) {
+	var label []byte
+
+	label, n = parseMacroUIText(text)
+	if n == 0 {
+		return nil, 0
+	}
+	el = &element{
+		kind: elKindInlineButton,
+		raw:  label,
+	}
+	return el, n
+}
+
+// parseMacroKbd parse the keyboard macro,
+//
+//	"kbd:[" KEY *(("+" / ",") KEY) "]"
+func parseMacroKbd(text []byte) (el *element, n int) {
+	var keys []byte
+
+	keys, n = parseMacroUIText(text)
+	if n == 0 {
+		return nil, 0
+	}
+	el = &element{
+		kind: elKindInlineKbd,
+		raw:  keys,
+	}
+	return el, n
+}
+
+// parseMacroMenu parse the menu macro,
+//
+//	"menu:" MENU "[" [ ITEM *(">" ITEM) ] "]"
+//
+// The MENU must not contains spaces.
+func parseMacroMenu(text []byte) (el *element, n int) {
+	var (
+		menu []byte
+		x    int
+	)
+
+	menu, x = indexByteUnescape(text, '[')
+	if x <= 0 || bytes.ContainsAny(menu, " \t") {
+		return nil, 0
+	}
+
+	var items []byte
+
+	items, n = parseMacroUIText(text[x:])
+	if n == 0 {
+		return nil, 0
+	}
+	el = &element{
+		kind: elKindInlineMenu,
+		key:  string(menu),
+		raw:  items,
+	}
+	return el, x + n
+}
+
+// parseMacroUIText parse the text inside the square brackets, with "]"
+// can be escaped using backslash.
+// It will return the text with the number of bytes consumed, including
+// the ":" before the text, or zero if the text is not enclosed by square
+// brackets.
+func parseMacroUIText(text []byte) (out []byte, n int) {
+	if len(text) == 0 || text[0] != '[' {
+		return nil, 0
+	}
+
+	var x int
+
+	out, x = indexByteUnescape(text[1:], ']')
+	if x < 0 {
+		return nil, 0
+	}
+	return bytes.TrimSpace(out), x + 3
+}
+
+// macroKbdKeys split the keys in keyboard macro by "+" or ",", whichever
+// found first.
+// The delimiter at the end of keys is the key itself, for example "Ctrl++"
+// is "Ctrl" and "+".
+// The raw keys must be HTML escaped.
+func macroKbdKeys(raw string) (keys []string) {
+	if len(raw) <= 1 {
+		return []string{raw}
+	}
+
+	var x = strings.IndexAny(raw[1:], `+,`)
+	if x < 0 {
+		return []string{raw}
+	}
+
+	var (
+		delim   = raw[x+1 : x+2]
+		lastKey string
+		key     string
+	)
+	if strings.HasSuffix(raw, delim) {
+		raw = raw[:len(raw)-1]
+		lastKey = delim
+	}
+	for _, key = range strings.Split(raw, delim) {
+		keys = append(keys, strings.TrimSpace(key))
+	}
+	if len(lastKey) > 0 {
+		keys[len(keys)-1] += lastKey
+	}
+	return keys
+}
+
+// macroMenuItems split the menu items by ">" or ",".
+// The raw items must be HTML escaped.
+func macroMenuItems(raw string) (items []string) {
+	var delim = `&gt;`
+	if !strings.Contains(raw, delim) {
+		delim = `,`
+	}
+
+	var item string
+	for _, item = range strings.Split(raw, delim) {
+		item = strings.TrimSpace(item)
+		if len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func htmlWriteInlineButton(el *element, out io.Writer) {
+	fmt.Fprintf(out, `<b class="button">%s</b>`, htmlSubsChar(el.raw))
+}
+
+func htmlWriteInlineKbd(el *element, out io.Writer) {
+	var keys = macroKbdKeys(string(htmlSubsChar(el.raw)))
+
+	if len(keys) == 1 {
+		fmt.Fprintf(out, `<kbd>%s</kbd>`, keys[0])
+		return
+	}
+
+	var (
+		key string
+		x   int
+	)
+	fmt.Fprint(out, `<span class="keyseq">`)
+	for x, key = range keys {
+		if x > 0 {
+			fmt.Fprint(out, `+`)
+		}
+		fmt.Fprintf(out, `<kbd>%s</kbd>`, key)
+	}
+	fmt.Fprint(out, `</span>`)
+}
+
+func htmlWriteInlineMenu(el *element, out io.Writer) {
+	var (
+		items = macroMenuItems(string(htmlSubsChar(el.raw)))
+		menu  = htmlSubsChar([]byte(el.key))
+	)
+
+	if len(items) == 0 {
+		fmt.Fprintf(out, `<b class="menuref">%s</b>`, menu)
+		return
+	}
+
+	var (
+		item string
+		x    int
+	)
+	fmt.Fprintf(out, `<span class="menuseq"><b class="menu">%s</b>`, menu)
+	for x, item = range items {
+		fmt.Fprint(out, htmlMenuCaret)
+		if x == len(items)-1 {
+			fmt.Fprintf(out, `<b class="menuitem">%s</b>`, item)
+		} else {
+			fmt.Fprintf(out, `<b class="submenu">%s</b>`, item)
+		}
+	}
+	fmt.Fprint(out, `</span>`)
+}
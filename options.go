@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// envSourceDateEpoch is the environment variable that contains the number
+// of seconds since Unix epoch to be used as the document and local time.
+//
+// Ref: https://reproducible-builds.org/specs/source-date-epoch/
+const envSourceDateEpoch = `SOURCE_DATE_EPOCH`
+
+// IncludeResolver is the function to read the content of "include::"
+// directive.
+//
+// The target is the path or URI inside the include directive, after the
+// attribute references substituted.
+// The attrs contains the attributes of include directive, for example
+// "lines" or "tags"; it must not be modified.
+// The from parameter is the path of file that contains the directive, the
+// path of document being parsed or the target of parent include directive
+// for nested include.
+// It is empty if the directive is in the document created using
+// [ParseWithOptions].
+//
+// If the returned error wraps [io/fs.ErrNotExist] the include is reported
+// as "include file not found".
+type IncludeResolver func(target string, attrs map[string]string, from string) (content []byte, err error)
+
+// Options define the options to open or parse the document.
+type Options struct {
+	// IncludeResolver define the function to read the content of
+	// include directive.
+	// If its nil, the content is read from the file system, relative
+	// to the document directory.
+	//
+	// The URI target, for example "https://example.com/file.adoc", is
+	// passed to the resolver only if AllowURIRead is true.
+	// Otherwise, the include directive is replaced with link to the URI.
+	// This library never read the URI from the network by itself.
+	IncludeResolver IncludeResolver
+
+	// Clock define the function that return the current time.
+	// If its not nil, the returned time is used for all date and time
+	// attributes, including the document modification time in
+	// "docdate", "doctime", and "last-update-value".
+	// The Clock has higher priority than the environment variable
+	// SOURCE_DATE_EPOCH.
+	Clock func() time.Time
+
+	// AllowURIRead allow the include directive with URI target to be
+	// read by IncludeResolver.
+	// If its true, the document attribute "allow-uri-read" is set.
+	// The "allow-uri-read" that set or unset inside the document is
+	// ignored.
+	AllowURIRead bool
+}
+
+// now return the current time from Clock, or from the environment variable
+// SOURCE_DATE_EPOCH.
+// The isFixed is true if the time is from one of them, otherwise it
+// return the [time.Now].
+// It will return non-nil error if the SOURCE_DATE_EPOCH is not a valid
+// number.
+func (opts Options) now() (t time.Time, isFixed bool, err error) {
+	if opts.Clock != nil {
+		return opts.Clock(), true, nil
+	}
+
+	var epoch = os.Getenv(envSourceDateEpoch)
+	if len(epoch) == 0 {
+		return time.Now(), false, nil
+	}
+
+	var sec int64
+	sec, err = strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Now(), false, fmt.Errorf(`invalid %s %q`, envSourceDateEpoch, epoch)
+	}
+	return time.Unix(sec, 0).UTC(), true, nil
+}
@@ -16,13 +16,13 @@ const (
 	elKindPreamble                   // Wrapper.
 	elKindDocContent                 // Wrapper.
 	elKindSectionL0                  // Line started with "="
-	elKindSectionL1                  // Line started with "=="
-	elKindSectionL2                  // 5: Line started with "==="
+	elKindSectionL1                  // 5: Line started with "=="
+	elKindSectionL2                  // Line started with "==="
 	elKindSectionL3                  // Line started with "===="
 	elKindSectionL4                  // Line started with "====="
 	elKindSectionL5                  // Line started with "======"
-	elKindSectionDiscrete            // "[discrete]"
-	elKindParagraph                  // 10: Wrapper.
+	elKindSectionDiscrete            // 10: "[discrete]"
+	elKindParagraph                  // Wrapper.
 	elKindLiteralParagraph           // Line start with space
 	elKindBlockAudio                 // "audio::"
 	elKindBlockExample               // "===="
@@ -31,41 +31,50 @@ const (
 	elKindBlockListing               // "----"
 	elKindBlockListingNamed          // "[listing]"
 	elKindBlockLiteral               // "...."
-	elKindBlockLiteralNamed          // "[literal]"
-	elKindBlockOpen                  // 20: Block wrapped with "--"
+	elKindBlockLiteralNamed          // 20: "[literal]"
+	elKindBlockOpen                  // Block wrapped with "--"
 	elKindBlockPassthrough           // Block wrapped with "++++"
 	elKindBlockSidebar               // "****"
 	elKindBlockVideo                 // "video::"
 	elKindCrossReference             // "<<" REF ("," LABEL) ">>"
 	elKindFootnote                   // footnote:id[]
+	elKindInlineButton               // Inline macro "btn:"
 	elKindInlineID                   // "[[" REF_ID "]]" TEXT
-	elKindInlineIDShort              // "[#" REF_ID "]#" TEXT "#"
-	elKindInlineImage                // Inline macro for "image:"
+	elKindInlineIDShort              // "[" INLINE_ATTRS "]#" TEXT "#"
+	elKindInlineImage                // 30: Inline macro for "image:"
+	elKindInlineKbd                  // Inline macro "kbd:"
+	elKindInlineMenu                 // Inline macro "menu:"
 	elKindInlinePass                 // Inline macro for passthrough "pass:"
+	elKindInlineStem                 // Inline macro "stem:", "asciimath:", or "latexmath:"
+	elKindIndexTerm                  // "((" TERM "))" or "(((" TERMS ")))"
 	elKindInlineParagraph            //
 	elKindListOrdered                // Wrapper.
-	elKindListOrderedItem            // 30: Line start with ". "
+	elKindListOrderedItem            // Line start with ". "
 	elKindListUnordered              // Wrapper.
-	elKindListUnorderedItem          // Line start with "* " or "- "
+	elKindListUnorderedItem          // 40: Line start with "* " or "- "
 	elKindListDescription            // Wrapper.
 	elKindListDescriptionItem        // Line that has "::" + WSP
+	elKindListCallout                // Wrapper.
+	elKindListCalloutItem            // Line start with "<" NUMBER ">" WSP
 	elKindMacroTOC                   // "toc::[]"
 	elKindPassthrough                // Text wrapped inside "+"
 	elKindPassthroughDouble          // Text wrapped inside "++"
 	elKindPassthroughTriple          // Text wrapped inside "+++"
 	elKindSymbolQuoteDoubleBegin     // The ("`)
-	elKindSymbolQuoteDoubleEnd       // 40: The (`")
+	elKindSymbolQuoteDoubleEnd       // 50: The (`")
 	elKindSymbolQuoteSingleBegin     // The ('`)
 	elKindSymbolQuoteSingleEnd       // The (`')
 	elKindTable                      // "|==="
 	elKindText                       //
 	elKindTextBold                   // Text wrapped by "*"
 	elKindTextItalic                 // Text wrapped by "_"
+	elKindTextMark                   // Text wrapped by "#"
 	elKindTextMono                   // Text wrapped by "`"
 	elKindTextSubscript              // Word wrapped by '~'
-	elKindTextSuperscript            // Word wrapped by '^'
-	elKindUnconstrainedBold          // 50: Text wrapped by "**"
+	elKindTextSuperscript            // 60: Word wrapped by '^'
+	elKindUnconstrainedBold          // Text wrapped by "**"
 	elKindUnconstrainedItalic        // Text wrapped by "__"
+	elKindUnconstrainedMark          // Text wrapped by "##"
 	elKindUnconstrainedMono          // Text wrapped by "``"
 	elKindURL                        // Anchor text.
 	lineKindAdmonition               // "LABEL: WSP"
@@ -74,7 +83,7 @@ const (
 	lineKindBlockComment             // Block start and end with "////"
 	lineKindBlockTitle               // Line start with ".<alnum>"
 	lineKindComment                  // Line start with "//"
-	lineKindEmpty                    // 60: LF
+	lineKindEmpty                    // LF
 	lineKindHorizontalRule           // "'''", "---", "- - -", "***", "* * *"
 	lineKindID                       // "[[" REF_ID "]]"
 	lineKindIDShort                  // "[#" REF_ID "]#" TEXT "#"
@@ -98,9 +107,12 @@ const (
 	attrNameFrame       = `frame`
 	attrNameGrid        = `grid`
 	attrNameHeight      = `height`
+	attrNameHighlight   = `highlight`
 	attrNameHref        = `href`
 	attrNameIcons       = `icons`
+	attrNameIndent      = `indent`
 	attrNameLang        = `lang`
+	attrNameLines       = `lines`
 	attrNameLink        = `link`
 	attrNameOptions     = `options`
 	attrNameOpts        = `opts`
@@ -112,6 +124,9 @@ const (
 	attrNameSrc         = `src`
 	attrNameStart       = `start`
 	attrNameStripes     = `stripes`
+	attrNameSubs        = `subs`
+	attrNameTag         = `tag`
+	attrNameTags        = `tags`
 	attrNameTarget      = `target`
 	attrNameTheme       = `theme`
 	attrNameTitle       = `title`
@@ -128,6 +143,8 @@ const (
 	attrValueBlank     = `_blank`
 	attrValueCols      = `cols`
 	attrValueContent   = `content`
+	attrValueDrop      = `drop`
+	attrValueDropLine  = `drop-line`
 	attrValueEmail     = `email`
 	attrValueEven      = `even`
 	attrValueFont      = `font`
@@ -143,12 +160,15 @@ const (
 	attrValueRevNumber = `revnumber`
 	attrValueRows      = `rows`
 	attrValueSides     = `sides`
+	attrValueSkip      = `skip`
 	attrValueTitle     = attrNameTitle
 	attrValueTopbot    = `topbot`
+	attrValueWarn      = `warn`
 )
 
 const (
 	classNameArabic       = `arabic`
+	classNameBibliography = `bibliography`
 	classNameChecklist    = `checklist`
 	classNameFitContent   = `fit-content`
 	classNameFrameAll     = `frame-all`
@@ -175,8 +195,11 @@ const (
 	optNameAutoplay               = `autoplay`
 	optNameAutowidth              = `autowidth`
 	optNameControls               = `controls`
+	optNameHardbreaks             = `hardbreaks`
+	optNameLinenums               = `linenums`
 	optNameLoop                   = `loop`
 	optNameNocontrols             = `nocontrols`
+	optNameOptional               = `optional`
 	optVideoFullscreen            = `fs`
 	optVideoModest                = `modest`
 	optVideoNofullscreen          = `nofullscreen`
@@ -229,8 +252,10 @@ const (
 	styleBlockListing
 	styleQuote
 	styleSource
+	styleStem
 	styleTextBold
 	styleTextItalic
+	styleTextMark
 	styleTextMono
 	styleVerse
 )
@@ -279,6 +304,9 @@ var adocStyles = map[string]int64{
 	`listing`:           styleBlockListing,
 	`quote`:             styleQuote,
 	`source`:            styleSource,
+	macroStem:           styleStem,
+	macroAsciimath:      styleStem,
+	macroLatexmath:      styleStem,
 	`verse`:             styleVerse,
 }
 
@@ -316,24 +344,36 @@ var _attrRef = map[string]string{
 
 // preprocessBlockCode preprocess the content of block code, like "include::"
 // directive, and return the new content.
-func preprocessBlockCode(doc *Document, content []byte) (newContent []byte) {
+// The escaped directive, "\include::", is written without the backslash.
+// The from is the path of file that contains the content, and the lineNum
+// is the line number of the first line in content, or zero if its unknown.
+func preprocessBlockCode(doc *Document, content []byte, from string, lineNum int) (newContent []byte) {
 	var bbuf bytes.Buffer
 	var lines = bytes.Split(content, []byte{'\n'})
-	for _, line := range lines {
+	for x, line := range lines {
+		if bytes.HasPrefix(line, []byte(`\include::`)) {
+			bbuf.Write(line[1:])
+			bbuf.WriteByte('\n')
+			continue
+		}
 		if bytes.HasPrefix(line, []byte(`include::`)) {
-			var elInclude = parseInclude(doc, line)
+			var n int
+			if lineNum > 0 {
+				n = lineNum + x
+			}
+			var elInclude = parseInclude(doc, line, from, n)
 			if elInclude != nil {
-				bbuf.Write(elInclude.content)
-				bbuf.WriteByte('\n')
+				if len(elInclude.content) != 0 {
+					bbuf.Write(elInclude.content)
+					bbuf.WriteByte('\n')
+				}
 				continue
 			}
 		}
 		bbuf.Write(line)
 		bbuf.WriteByte('\n')
 	}
-
-	newContent = applySubstitutions(doc, bbuf.Bytes())
-	return newContent
+	return bbuf.Bytes()
 }
 
 // applySubstitutions scan the content and replace attribute reference "{}"
@@ -478,6 +518,11 @@ func isAdmonition(line []byte) bool {
 	return false
 }
 
+// isLineDescriptionItem return true if line is description list item,
+// a term followed by "::", ":::", or "::::", and then white space or end of
+// line.
+// The "::" that followed by other character, for example in
+// "include::file[]", is part of the term.
 func isLineDescriptionItem(line []byte) bool {
 	var (
 		x int
@@ -491,8 +536,16 @@ func isLineDescriptionItem(line []byte) bool {
 	if x > 0 {
 		return true
 	}
-	_, x = indexUnescape(line, []byte(`::`))
-	return x > 0
+
+	// The term without description must end with "::".
+	var (
+		term   = bytes.TrimRight(line, `:`)
+		ncolon = len(line) - len(term)
+	)
+	if ncolon < 2 || ncolon > 4 || len(term) == 0 {
+		return false
+	}
+	return term[len(term)-1] != '\\'
 }
 
 func isStyleAdmonition(style int64) bool {
@@ -540,7 +593,6 @@ func parseAttrRef(doc *Document, content []byte, x int) (newContent []byte, ok b
 	var (
 		raw = content[x+1:]
 
-		name      string
 		attrValue string
 		attrName  []byte
 		rest      []byte
@@ -552,19 +604,9 @@ func parseAttrRef(doc *Document, content []byte, x int) (newContent []byte, ok b
 		return nil, false
 	}
 
-	name = string(bytes.TrimSpace(bytes.ToLower(attrName)))
-	attrValue, ok = _attrRef[name]
+	attrValue, ok = doc.attrRefValue(string(attrName))
 	if !ok {
-		attrValue, ok = doc.Attributes.Entry[name]
-		if !ok {
-			return nil, false
-		}
-
-		// Add prefix "mailto:" if the ref name start with email, so
-		// it can be parsed by caller as macro link.
-		if name == `email` || strings.HasPrefix(name, `email_`) {
-			attrValue = `mailto:` + attrValue + `[` + attrValue + `]`
-		}
+		return nil, false
 	}
 
 	rest = content[x+idx+2:]
@@ -574,6 +616,35 @@ func parseAttrRef(doc *Document, content []byte, x int) (newContent []byte, ok b
 	return newContent, true
 }
 
+// attrRefValue return the value of attribute reference name, the text
+// between "{" and "}".
+func (doc *Document) attrRefValue(name string) (val string, ok bool) {
+	val, ok = doc.counterRef(name)
+	if ok {
+		return val, true
+	}
+	if doc.setRef(name) {
+		return ``, true
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	val, ok = _attrRef[name]
+	if ok {
+		return val, true
+	}
+	val, ok = doc.Attributes.Entry[name]
+	if !ok {
+		return ``, false
+	}
+
+	// Add prefix "mailto:" if the ref name start with email, so
+	// it can be parsed by caller as macro link.
+	if name == `email` || strings.HasPrefix(name, `email_`) {
+		val = `mailto:` + val + `[` + val + `]`
+	}
+	return val, true
+}
+
 // parseClosedBracket parse the text in input until we found the last close
 // bracket.
 // It will skip any open-close brackets inside input.
@@ -657,6 +728,16 @@ func parseInlineMarkup(doc *Document, content []byte) (container *element) {
 	return pi.container
 }
 
+// parseInlineMarkupHardbreaks parse the inline markup in content and
+// convert each line feed into line break.
+func parseInlineMarkupHardbreaks(doc *Document, content []byte) (container *element) {
+	var pi = newInlineParser(doc, content)
+
+	pi.isHardbreaks = true
+	pi.do()
+	return pi.container
+}
+
 // parseStyle get the style based on string value.
 func parseStyle(styleName string) (styleKind int64) {
 	// Check for admonition label first...
@@ -163,3 +163,45 @@ func TestIsValidID(t *testing.T) {
 		test.Assert(t, c.id, c.exp, got)
 	}
 }
+
+func TestIsLineDescriptionItem(t *testing.T) {
+	type testCase struct {
+		line string
+		exp  bool
+	}
+
+	var cases = []testCase{{
+		line: `term:: description`,
+		exp:  true,
+	}, {
+		line: "term::\tdescription",
+		exp:  true,
+	}, {
+		line: `term::`,
+		exp:  true,
+	}, {
+		line: `term:::`,
+		exp:  true,
+	}, {
+		line: `term::::`,
+		exp:  true,
+	}, {
+		line: `a::b::`,
+		exp:  true,
+	}, {
+		line: `term:::::`,
+	}, {
+		line: `::`,
+	}, {
+		line: `term\::`,
+	}, {
+		line: `a::b`,
+	}, {
+		line: `Unresolved directive in <stdin> - include::missing.adoc[]`,
+	}}
+
+	var c testCase
+	for _, c = range cases {
+		test.Assert(t, c.line, c.exp, isLineDescriptionItem([]byte(c.line)))
+	}
+}
@@ -13,6 +13,14 @@ type sectionCounters struct {
 	// index 1 represent coutner for level 1, and so on.
 	nums [6]byte
 	curr int
+
+	// appendix contains the letter of current appendix section, or 0 if
+	// the current section is not inside appendix.
+	appendix byte
+
+	// special contains the level of current special section, or 0 if
+	// the current section is not inside special section.
+	special int
 }
 
 func (sec *sectionCounters) set(level int) *sectionCounters {
@@ -38,6 +46,17 @@ func (sec *sectionCounters) set(level int) *sectionCounters {
 	return &clone
 }
 
+// setAppendix set the counters for the appendix section at level 1 with
+// letter as its number, and reset the counters for sub sections.
+func (sec *sectionCounters) setAppendix(letter byte) {
+	var x int
+	for x = 2; x < len(sec.nums); x++ {
+		sec.nums[x] = 0
+	}
+	sec.appendix = letter
+	sec.curr = 1
+}
+
 func (sec *sectionCounters) String() string {
 	var (
 		sb strings.Builder
@@ -45,6 +64,10 @@ func (sec *sectionCounters) String() string {
 	)
 
 	for x = 1; x < 6; x++ {
+		if x == 1 && sec.appendix != 0 {
+			fmt.Fprintf(&sb, `%c.`, sec.appendix)
+			continue
+		}
 		if sec.nums[x] == 0 {
 			break
 		}
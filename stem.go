@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// List of STEM notations.
+const (
+	stemNotationAsciimath = `asciimath`
+	stemNotationLatexmath = `latexmath`
+)
+
+// mathNode contains the MathML of parsed expression.
+type mathNode struct {
+	mml string
+
+	// body contains the MathML of expression inside the brackets or
+	// group, without the brackets.
+	body string
+
+	// isBracket set to true if the expression is inside brackets or
+	// group.
+	// The brackets are removed when the expression become the argument
+	// of fraction, root, sub-script, or super-script.
+	isBracket bool
+
+	isUnderOver bool
+}
+
+// inner return the MathML of node without the brackets.
+func (node mathNode) inner() string {
+	if node.isBracket {
+		return `<mrow>` + node.body + `</mrow>`
+	}
+	return node.mml
+}
+
+// mathTag return the MathML element tag with content.
+func mathTag(tag, content string) string {
+	return `<` + tag + `>` + content + `</` + tag + `>`
+}
+
+// mathEscape escape the special HTML characters in text.
+func mathEscape(text string) string {
+	return string(htmlSubsChar([]byte(text)))
+}
+
+// stemNotation return the notation for the macro or block style name.
+// The "stem" use the value of document attribute "stem", default to
+// "asciimath".
+func stemNotation(doc *Document, name string) string {
+	name = strings.ToLower(name)
+	switch name {
+	case stemNotationAsciimath, stemNotationLatexmath:
+		return name
+	}
+	switch doc.Attributes.Entry[docAttrStem] {
+	case stemNotationLatexmath, `latex`, `tex`:
+		return stemNotationLatexmath
+	}
+	return stemNotationAsciimath
+}
+
+// stemToMathML convert the STEM expression based on its notation into
+// MathML element "math".
+func stemToMathML(notation, text string, isBlock bool) string {
+	var (
+		display = `inline`
+		content string
+	)
+	if isBlock {
+		display = `block`
+	}
+	if notation == stemNotationLatexmath {
+		content = latexmathToMathML(text)
+	} else {
+		content = asciimathToMathML(text)
+	}
+	return fmt.Sprintf(`<math xmlns="http://www.w3.org/1998/Math/MathML" display=%q>%s</math>`,
+		display, content)
+}
+
+// parseMacroStem parse the inline STEM macro,
+//
+//	("stem" / "asciimath" / "latexmath") ":[" TEXT "]"
+//
+// The "]" inside the TEXT must be escaped with backslash.
+func parseMacroStem(doc *Document, name string, text []byte) (el *element, n int) {
+	if len(text) == 0 || text[0] != '[' {
+		return nil, 0
+	}
+
+	var (
+		raw []byte
+		x   int
+	)
+	for x = 1; x < len(text); x++ {
+		if text[x] == ']' {
+			if text[x-1] != '\\' {
+				break
+			}
+			raw = raw[:len(raw)-1]
+		}
+		raw = append(raw, text[x])
+	}
+	if x == len(text) {
+		return nil, 0
+	}
+
+	el = &element{
+		kind: elKindInlineStem,
+		key:  stemNotation(doc, name),
+		raw:  raw,
+	}
+	return el, x + 2
+}
+
+func htmlWriteInlineStem(el *element, out io.Writer) {
+	var text = htmlUnescape(string(el.raw))
+
+	fmt.Fprint(out, stemToMathML(el.key, text, false))
+}
+
+func htmlWriteBlockStem(el *element, out io.Writer) {
+	var text = string(bytes.TrimSpace(el.raw))
+
+	htmlWriteBlockBegin(el, out, `stemblock`)
+	fmt.Fprintf(out, "\n<div class=%q>\n%s\n</div>\n</div>",
+		attrValueContent, stemToMathML(el.key, text, true))
+}
@@ -0,0 +1,533 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"strings"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/ascii"
+)
+
+// List of AsciiMath symbol kind.
+const (
+	amKindConst = iota
+	amKindUnary
+	amKindBinary
+	amKindBracketLeft
+	amKindBracketRight
+	amKindText
+)
+
+// amSymbol define the AsciiMath symbol and its MathML output.
+type amSymbol struct {
+	// tag is the MathML element for amKindConst, for example "mo", or
+	// the element that wrap the argument for amKindUnary and
+	// amKindBinary, for example "msqrt".
+	tag string
+
+	// out is the content of MathML element.
+	// For accent, it is the accent character.
+	// For font, it is the value of mathvariant.
+	out string
+
+	kind int
+
+	// isUnderOver set the sub-script and super-script to be rendered
+	// under and over the symbol, for example "sum".
+	isUnderOver bool
+}
+
+// amSymbols contains the AsciiMath symbols.
+// Ref: https://asciimath.org/#syntax
+var amSymbols = map[string]amSymbol{
+	// Operation symbols.
+	`+`:    {tag: `mo`, out: `+`},
+	`-`:    {tag: `mo`, out: `-`},
+	`*`:    {tag: `mo`, out: `⋅`},
+	`**`:   {tag: `mo`, out: `∗`},
+	`***`:  {tag: `mo`, out: `⋆`},
+	`//`:   {tag: `mo`, out: `/`},
+	`\\`:   {tag: `mo`, out: `\`},
+	`xx`:   {tag: `mo`, out: `×`},
+	`-:`:   {tag: `mo`, out: `÷`},
+	`|><`:  {tag: `mo`, out: `⋉`},
+	`><|`:  {tag: `mo`, out: `⋊`},
+	`|><|`: {tag: `mo`, out: `⋈`},
+	`@`:    {tag: `mo`, out: `∘`},
+	`o+`:   {tag: `mo`, out: `⊕`},
+	`ox`:   {tag: `mo`, out: `⊗`},
+	`o.`:   {tag: `mo`, out: `⊙`},
+	`sum`:  {tag: `mo`, out: `∑`, isUnderOver: true},
+	`prod`: {tag: `mo`, out: `∏`, isUnderOver: true},
+	`^^`:   {tag: `mo`, out: `∧`},
+	`^^^`:  {tag: `mo`, out: `⋀`, isUnderOver: true},
+	`vv`:   {tag: `mo`, out: `∨`},
+	`vvv`:  {tag: `mo`, out: `⋁`, isUnderOver: true},
+	`nn`:   {tag: `mo`, out: `∩`},
+	`nnn`:  {tag: `mo`, out: `⋂`, isUnderOver: true},
+	`uu`:   {tag: `mo`, out: `∪`},
+	`uuu`:  {tag: `mo`, out: `⋃`, isUnderOver: true},
+
+	// Relation symbols.
+	`=`:    {tag: `mo`, out: `=`},
+	`!=`:   {tag: `mo`, out: `≠`},
+	`<`:    {tag: `mo`, out: `&lt;`},
+	`>`:    {tag: `mo`, out: `&gt;`},
+	`<=`:   {tag: `mo`, out: `≤`},
+	`>=`:   {tag: `mo`, out: `≥`},
+	`lt`:   {tag: `mo`, out: `&lt;`},
+	`gt`:   {tag: `mo`, out: `&gt;`},
+	`le`:   {tag: `mo`, out: `≤`},
+	`ge`:   {tag: `mo`, out: `≥`},
+	`-<`:   {tag: `mo`, out: `≺`},
+	`>-`:   {tag: `mo`, out: `≻`},
+	`in`:   {tag: `mo`, out: `∈`},
+	`!in`:  {tag: `mo`, out: `∉`},
+	`sub`:  {tag: `mo`, out: `⊂`},
+	`sup`:  {tag: `mo`, out: `⊃`},
+	`sube`: {tag: `mo`, out: `⊆`},
+	`supe`: {tag: `mo`, out: `⊇`},
+	`-=`:   {tag: `mo`, out: `≡`},
+	`~=`:   {tag: `mo`, out: `≅`},
+	`~~`:   {tag: `mo`, out: `≈`},
+	`prop`: {tag: `mo`, out: `∝`},
+
+	// Logical symbols.
+	`and`: {tag: `mtext`, out: ` and `},
+	`or`:  {tag: `mtext`, out: ` or `},
+	`not`: {tag: `mo`, out: `¬`},
+	`=>`:  {tag: `mo`, out: `⇒`},
+	`if`:  {tag: `mtext`, out: ` if `},
+	`<=>`: {tag: `mo`, out: `⇔`},
+	`AA`:  {tag: `mo`, out: `∀`},
+	`EE`:  {tag: `mo`, out: `∃`},
+	`_|_`: {tag: `mo`, out: `⊥`},
+	`TT`:  {tag: `mo`, out: `⊤`},
+	`|--`: {tag: `mo`, out: `⊢`},
+	`|==`: {tag: `mo`, out: `⊨`},
+
+	// Miscellaneous symbols.
+	`int`:   {tag: `mo`, out: `∫`},
+	`oint`:  {tag: `mo`, out: `∮`},
+	`del`:   {tag: `mo`, out: `∂`},
+	`grad`:  {tag: `mo`, out: `∇`},
+	`+-`:    {tag: `mo`, out: `±`},
+	`O/`:    {tag: `mo`, out: `∅`},
+	`oo`:    {tag: `mi`, out: `∞`},
+	`aleph`: {tag: `mi`, out: `ℵ`},
+	`...`:   {tag: `mo`, out: `…`},
+	`cdots`: {tag: `mo`, out: `⋯`},
+	`vdots`: {tag: `mo`, out: `⋮`},
+	`ddots`: {tag: `mo`, out: `⋱`},
+	`/_`:    {tag: `mo`, out: `∠`},
+	`:.`:    {tag: `mo`, out: `∴`},
+	`'`:     {tag: `mo`, out: `′`},
+	`quad`:  {tag: `mspace`, out: `1em`},
+	`CC`:    {tag: `mi`, out: `ℂ`},
+	`NN`:    {tag: `mi`, out: `ℕ`},
+	`QQ`:    {tag: `mi`, out: `ℚ`},
+	`RR`:    {tag: `mi`, out: `ℝ`},
+	`ZZ`:    {tag: `mi`, out: `ℤ`},
+
+	// Arrows.
+	`uarr`: {tag: `mo`, out: `↑`},
+	`darr`: {tag: `mo`, out: `↓`},
+	`rarr`: {tag: `mo`, out: `→`},
+	`->`:   {tag: `mo`, out: `→`},
+	`>->`:  {tag: `mo`, out: `↣`},
+	`->>`:  {tag: `mo`, out: `↠`},
+	`>->>`: {tag: `mo`, out: `⤖`},
+	`|->`:  {tag: `mo`, out: `↦`},
+	`larr`: {tag: `mo`, out: `←`},
+	`harr`: {tag: `mo`, out: `↔`},
+	`rArr`: {tag: `mo`, out: `⇒`},
+	`lArr`: {tag: `mo`, out: `⇐`},
+	`hArr`: {tag: `mo`, out: `⇔`},
+
+	// Functions.
+	`sin`:  {tag: `mi`, out: `sin`},
+	`cos`:  {tag: `mi`, out: `cos`},
+	`tan`:  {tag: `mi`, out: `tan`},
+	`sec`:  {tag: `mi`, out: `sec`},
+	`csc`:  {tag: `mi`, out: `csc`},
+	`cot`:  {tag: `mi`, out: `cot`},
+	`sinh`: {tag: `mi`, out: `sinh`},
+	`cosh`: {tag: `mi`, out: `cosh`},
+	`tanh`: {tag: `mi`, out: `tanh`},
+	`exp`:  {tag: `mi`, out: `exp`},
+	`log`:  {tag: `mi`, out: `log`},
+	`ln`:   {tag: `mi`, out: `ln`},
+	`det`:  {tag: `mi`, out: `det`},
+	`dim`:  {tag: `mi`, out: `dim`},
+	`mod`:  {tag: `mi`, out: `mod`},
+	`gcd`:  {tag: `mi`, out: `gcd`},
+	`lcm`:  {tag: `mi`, out: `lcm`},
+	`lim`:  {tag: `mo`, out: `lim`, isUnderOver: true},
+	`Lim`:  {tag: `mo`, out: `Lim`, isUnderOver: true},
+	`min`:  {tag: `mo`, out: `min`, isUnderOver: true},
+	`max`:  {tag: `mo`, out: `max`, isUnderOver: true},
+
+	// Greek letters.
+	`alpha`:      {tag: `mi`, out: `α`},
+	`beta`:       {tag: `mi`, out: `β`},
+	`gamma`:      {tag: `mi`, out: `γ`},
+	`Gamma`:      {tag: `mi`, out: `Γ`},
+	`delta`:      {tag: `mi`, out: `δ`},
+	`Delta`:      {tag: `mi`, out: `Δ`},
+	`epsilon`:    {tag: `mi`, out: `ε`},
+	`varepsilon`: {tag: `mi`, out: `ɛ`},
+	`zeta`:       {tag: `mi`, out: `ζ`},
+	`eta`:        {tag: `mi`, out: `η`},
+	`theta`:      {tag: `mi`, out: `θ`},
+	`Theta`:      {tag: `mi`, out: `Θ`},
+	`vartheta`:   {tag: `mi`, out: `ϑ`},
+	`iota`:       {tag: `mi`, out: `ι`},
+	`kappa`:      {tag: `mi`, out: `κ`},
+	`lambda`:     {tag: `mi`, out: `λ`},
+	`Lambda`:     {tag: `mi`, out: `Λ`},
+	`mu`:         {tag: `mi`, out: `μ`},
+	`nu`:         {tag: `mi`, out: `ν`},
+	`xi`:         {tag: `mi`, out: `ξ`},
+	`Xi`:         {tag: `mi`, out: `Ξ`},
+	`pi`:         {tag: `mi`, out: `π`},
+	`Pi`:         {tag: `mi`, out: `Π`},
+	`rho`:        {tag: `mi`, out: `ρ`},
+	`sigma`:      {tag: `mi`, out: `σ`},
+	`Sigma`:      {tag: `mi`, out: `Σ`},
+	`tau`:        {tag: `mi`, out: `τ`},
+	`upsilon`:    {tag: `mi`, out: `υ`},
+	`phi`:        {tag: `mi`, out: `ϕ`},
+	`varphi`:     {tag: `mi`, out: `φ`},
+	`Phi`:        {tag: `mi`, out: `Φ`},
+	`chi`:        {tag: `mi`, out: `χ`},
+	`psi`:        {tag: `mi`, out: `ψ`},
+	`Psi`:        {tag: `mi`, out: `Ψ`},
+	`omega`:      {tag: `mi`, out: `ω`},
+	`Omega`:      {tag: `mi`, out: `Ω`},
+
+	// Brackets.
+	`(`:  {kind: amKindBracketLeft, out: `(`},
+	`)`:  {kind: amKindBracketRight, out: `)`},
+	`[`:  {kind: amKindBracketLeft, out: `[`},
+	`]`:  {kind: amKindBracketRight, out: `]`},
+	`{`:  {kind: amKindBracketLeft, out: `{`},
+	`}`:  {kind: amKindBracketRight, out: `}`},
+	`(:`: {kind: amKindBracketLeft, out: `⟨`},
+	`:)`: {kind: amKindBracketRight, out: `⟩`},
+	`<<`: {kind: amKindBracketLeft, out: `⟨`},
+	`>>`: {kind: amKindBracketRight, out: `⟩`},
+	`{:`: {kind: amKindBracketLeft},
+	`:}`: {kind: amKindBracketRight},
+
+	// Unary functions.
+	`sqrt`:  {kind: amKindUnary, tag: `msqrt`},
+	`hat`:   {kind: amKindUnary, tag: `mover`, out: `^`},
+	`bar`:   {kind: amKindUnary, tag: `mover`, out: `¯`},
+	`vec`:   {kind: amKindUnary, tag: `mover`, out: `→`},
+	`dot`:   {kind: amKindUnary, tag: `mover`, out: `.`},
+	`ddot`:  {kind: amKindUnary, tag: `mover`, out: `..`},
+	`tilde`: {kind: amKindUnary, tag: `mover`, out: `~`},
+	`ul`:    {kind: amKindUnary, tag: `munder`, out: `̲`},
+	`abs`:   {kind: amKindUnary, tag: `mrow`, out: `|`},
+	`norm`:  {kind: amKindUnary, tag: `mrow`, out: `∥`},
+	`bb`:    {kind: amKindUnary, tag: `mstyle`, out: `bold`},
+	`bbb`:   {kind: amKindUnary, tag: `mstyle`, out: `double-struck`},
+	`cc`:    {kind: amKindUnary, tag: `mstyle`, out: `script`},
+	`tt`:    {kind: amKindUnary, tag: `mstyle`, out: `monospace`},
+	`fr`:    {kind: amKindUnary, tag: `mstyle`, out: `fraktur`},
+	`sf`:    {kind: amKindUnary, tag: `mstyle`, out: `sans-serif`},
+	`text`:  {kind: amKindText},
+
+	// Binary functions.
+	`frac`:     {kind: amKindBinary, tag: `mfrac`},
+	`root`:     {kind: amKindBinary, tag: `mroot`},
+	`stackrel`: {kind: amKindBinary, tag: `mover`},
+	`overset`:  {kind: amKindBinary, tag: `mover`},
+	`underset`: {kind: amKindBinary, tag: `munder`},
+}
+
+// amSymbolMaxLen is the maximum length of symbol in amSymbols.
+const amSymbolMaxLen = 10
+
+// amParser convert the AsciiMath notation into MathML.
+type amParser struct {
+	in string
+	x  int
+}
+
+// asciimathToMathML convert the AsciiMath expression into the content of
+// MathML element "math".
+func asciimathToMathML(in string) string {
+	var am = amParser{
+		in: in,
+	}
+	return am.parseExpr(false)
+}
+
+// next return the next token and its symbol, without consuming it.
+// The symbol kind is amKindConst with empty tag if the token is not a
+// symbol, for example number, identifier, or quoted text.
+func (am *amParser) next() (tok string, sym amSymbol) {
+	var (
+		x  int
+		ok bool
+		n  int
+	)
+
+	for am.x < len(am.in) && ascii.IsSpace(am.in[am.x]) {
+		am.x++
+	}
+	if am.x >= len(am.in) {
+		return ``, sym
+	}
+
+	n = min(amSymbolMaxLen, len(am.in)-am.x)
+	for ; n > 0; n-- {
+		tok = am.in[am.x : am.x+n]
+		sym, ok = amSymbols[tok]
+		if ok {
+			return tok, sym
+		}
+	}
+
+	var c = am.in[am.x]
+
+	switch {
+	case ascii.IsDigit(c):
+		x = am.x + 1
+		for x < len(am.in) && (ascii.IsDigit(am.in[x]) ||
+			(am.in[x] == '.' && x+1 < len(am.in) && ascii.IsDigit(am.in[x+1]))) {
+			x++
+		}
+		return am.in[am.x:x], amSymbol{tag: `mn`}
+
+	case c == '"':
+		x = strings.IndexByte(am.in[am.x+1:], '"')
+		if x < 0 {
+			return am.in[am.x:], amSymbol{tag: `mtext`}
+		}
+		return am.in[am.x : am.x+x+2], amSymbol{tag: `mtext`}
+
+	case ascii.IsAlpha(c):
+		return am.in[am.x : am.x+1], amSymbol{tag: `mi`}
+	}
+
+	// Consume one UTF-8 character as operator.
+	x = am.x + 1
+	for x < len(am.in) && am.in[x]&0xC0 == 0x80 {
+		x++
+	}
+	return am.in[am.x:x], amSymbol{tag: `mo`}
+}
+
+// parseExpr parse the expressions until the end of input or until the
+// closing bracket if inBracket is true,
+//
+//	E = I E / I "/" I E
+func (am *amParser) parseExpr(inBracket bool) string {
+	var (
+		out strings.Builder
+
+		node mathNode
+		den  mathNode
+		tok  string
+		sym  amSymbol
+	)
+
+	for {
+		tok, sym = am.next()
+		if len(tok) == 0 {
+			break
+		}
+		if sym.kind == amKindBracketRight {
+			if inBracket {
+				break
+			}
+			// Unmatched closing bracket.
+			am.x += len(tok)
+			out.WriteString(mathTag(`mo`, sym.out))
+			continue
+		}
+
+		node = am.parseIntermediate()
+
+		tok, _ = am.next()
+		if tok == `/` {
+			am.x += len(tok)
+			den = am.parseIntermediate()
+			node = mathNode{
+				mml: `<mfrac>` + node.inner() + den.inner() + `</mfrac>`,
+			}
+		}
+		out.WriteString(node.mml)
+	}
+	return out.String()
+}
+
+// parseIntermediate parse the simple expression with optional sub-script
+// and super-script,
+//
+//	I = S "_" S "^" S / S "_" S / S "^" S / S
+func (am *amParser) parseIntermediate() (node mathNode) {
+	var (
+		base = am.parseSimple()
+
+		sub mathNode
+		sup mathNode
+		tok string
+	)
+
+	tok, _ = am.next()
+	switch tok {
+	case `_`:
+		am.x += len(tok)
+		sub = am.parseSimple()
+
+		tok, _ = am.next()
+		if tok == `^` {
+			am.x += len(tok)
+			sup = am.parseSimple()
+			if base.isUnderOver {
+				return mathNode{mml: `<munderover>` + base.mml + sub.inner() + sup.inner() + `</munderover>`}
+			}
+			return mathNode{mml: `<msubsup>` + base.mml + sub.inner() + sup.inner() + `</msubsup>`}
+		}
+		if base.isUnderOver {
+			return mathNode{mml: `<munder>` + base.mml + sub.inner() + `</munder>`}
+		}
+		return mathNode{mml: `<msub>` + base.mml + sub.inner() + `</msub>`}
+
+	case `^`:
+		am.x += len(tok)
+		sup = am.parseSimple()
+		if base.isUnderOver {
+			return mathNode{mml: `<mover>` + base.mml + sup.inner() + `</mover>`}
+		}
+		return mathNode{mml: `<msup>` + base.mml + sup.inner() + `</msup>`}
+	}
+	return base
+}
+
+// parseSimple parse the simple expression,
+//
+//	S = v / "(" E ")" / unary S / binary S S
+func (am *amParser) parseSimple() (node mathNode) {
+	var tok, sym = am.next()
+
+	if len(tok) == 0 {
+		return node
+	}
+	am.x += len(tok)
+
+	switch sym.kind {
+	case amKindBracketLeft:
+		var (
+			left  = sym
+			body  = am.parseExpr(true)
+			right amSymbol
+		)
+		tok, right = am.next()
+		if right.kind == amKindBracketRight {
+			am.x += len(tok)
+		}
+		node = mathNode{
+			body:      body,
+			isBracket: true,
+		}
+		if len(left.out) > 0 {
+			body = mathTag(`mo`, left.out) + body
+		}
+		if len(right.out) > 0 {
+			body += mathTag(`mo`, right.out)
+		}
+		node.mml = `<mrow>` + body + `</mrow>`
+		return node
+
+	case amKindBracketRight:
+		return mathNode{mml: mathTag(`mo`, sym.out)}
+
+	case amKindUnary:
+		var arg = am.parseSimple()
+		return mathNode{mml: amUnary(sym, arg)}
+
+	case amKindBinary:
+		var (
+			arg1 = am.parseSimple()
+			arg2 = am.parseSimple()
+		)
+		if sym.tag == `mfrac` {
+			return mathNode{mml: `<mfrac>` + arg1.inner() + arg2.inner() + `</mfrac>`}
+		}
+		// The "root n x", "stackrel a b", "overset a b", and
+		// "underset a b" place the first argument after the second.
+		return mathNode{mml: `<` + sym.tag + `>` + arg2.inner() + arg1.inner() + `</` + sym.tag + `>`}
+
+	case amKindText:
+		return mathNode{mml: am.parseText()}
+	}
+
+	switch sym.tag {
+	case `mtext`:
+		if tok[0] == '"' {
+			tok = strings.Trim(tok, `"`)
+			return mathNode{mml: mathTag(`mtext`, mathEscape(tok))}
+		}
+		return mathNode{mml: mathTag(`mtext`, sym.out)}
+	case `mn`, `mi`:
+		if len(sym.out) == 0 {
+			return mathNode{mml: mathTag(sym.tag, tok)}
+		}
+	case `mo`:
+		if len(sym.out) == 0 {
+			return mathNode{mml: mathTag(sym.tag, mathEscape(tok))}
+		}
+	case `mspace`:
+		return mathNode{mml: `<mspace width="` + sym.out + `"/>`}
+	}
+	return mathNode{
+		mml:         mathTag(sym.tag, sym.out),
+		isUnderOver: sym.isUnderOver,
+	}
+}
+
+// parseText parse the argument of "text(...)" as is.
+func (am *amParser) parseText() string {
+	var tok, sym = am.next()
+
+	if sym.kind != amKindBracketLeft {
+		return mathTag(`mtext`, ``)
+	}
+	am.x += len(tok)
+
+	var (
+		text string
+		x    int
+	)
+	for x = am.x; x < len(am.in); x++ {
+		sym = amSymbols[am.in[x:x+1]]
+		if sym.kind == amKindBracketRight {
+			break
+		}
+	}
+	text = am.in[am.x:x]
+	am.x = min(x+1, len(am.in))
+	return mathTag(`mtext`, mathEscape(text))
+}
+
+// amUnary return the MathML for unary function with its argument.
+func amUnary(sym amSymbol, arg mathNode) string {
+	switch sym.tag {
+	case `msqrt`:
+		return `<msqrt>` + arg.inner() + `</msqrt>`
+	case `mover`, `munder`:
+		return `<` + sym.tag + ` accent="true">` + arg.inner() +
+			mathTag(`mo`, sym.out) + `</` + sym.tag + `>`
+	case `mrow`:
+		return `<mrow>` + mathTag(`mo`, sym.out) + arg.inner() +
+			mathTag(`mo`, sym.out) + `</mrow>`
+	case `mstyle`:
+		return `<mstyle mathvariant="` + sym.out + `">` + arg.inner() + `</mstyle>`
+	}
+	return arg.mml
+}
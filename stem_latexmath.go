@@ -0,0 +1,554 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"strings"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/ascii"
+)
+
+// List of LaTeX command kind.
+const (
+	lmKindConst = iota
+	lmKindAccent
+	lmKindFont
+	lmKindFrac
+	lmKindBinom
+	lmKindSqrt
+	lmKindSpace
+	lmKindText
+	lmKindLeft
+	lmKindRight
+)
+
+// lmCommand define the LaTeX command and its MathML output.
+type lmCommand struct {
+	// tag is the MathML element for lmKindConst, or the element that
+	// wrap the argument for lmKindAccent.
+	tag string
+
+	// out is the content of MathML element.
+	// For lmKindAccent, it is the accent character.
+	// For lmKindFont, it is the value of mathvariant.
+	// For lmKindSpace, it is the width of space.
+	out string
+
+	kind int
+
+	// isUnderOver set the sub-script and super-script to be rendered
+	// under and over the symbol, for example "\sum".
+	isUnderOver bool
+}
+
+// lmCommands contains the subset of LaTeX math commands, without the
+// backslash.
+var lmCommands = map[string]lmCommand{
+	// Greek letters.
+	`alpha`:      {tag: `mi`, out: `α`},
+	`beta`:       {tag: `mi`, out: `β`},
+	`gamma`:      {tag: `mi`, out: `γ`},
+	`Gamma`:      {tag: `mi`, out: `Γ`},
+	`delta`:      {tag: `mi`, out: `δ`},
+	`Delta`:      {tag: `mi`, out: `Δ`},
+	`epsilon`:    {tag: `mi`, out: `ϵ`},
+	`varepsilon`: {tag: `mi`, out: `ε`},
+	`zeta`:       {tag: `mi`, out: `ζ`},
+	`eta`:        {tag: `mi`, out: `η`},
+	`theta`:      {tag: `mi`, out: `θ`},
+	`Theta`:      {tag: `mi`, out: `Θ`},
+	`vartheta`:   {tag: `mi`, out: `ϑ`},
+	`iota`:       {tag: `mi`, out: `ι`},
+	`kappa`:      {tag: `mi`, out: `κ`},
+	`lambda`:     {tag: `mi`, out: `λ`},
+	`Lambda`:     {tag: `mi`, out: `Λ`},
+	`mu`:         {tag: `mi`, out: `μ`},
+	`nu`:         {tag: `mi`, out: `ν`},
+	`xi`:         {tag: `mi`, out: `ξ`},
+	`Xi`:         {tag: `mi`, out: `Ξ`},
+	`pi`:         {tag: `mi`, out: `π`},
+	`Pi`:         {tag: `mi`, out: `Π`},
+	`rho`:        {tag: `mi`, out: `ρ`},
+	`sigma`:      {tag: `mi`, out: `σ`},
+	`Sigma`:      {tag: `mi`, out: `Σ`},
+	`tau`:        {tag: `mi`, out: `τ`},
+	`upsilon`:    {tag: `mi`, out: `υ`},
+	`phi`:        {tag: `mi`, out: `ϕ`},
+	`varphi`:     {tag: `mi`, out: `φ`},
+	`Phi`:        {tag: `mi`, out: `Φ`},
+	`chi`:        {tag: `mi`, out: `χ`},
+	`psi`:        {tag: `mi`, out: `ψ`},
+	`Psi`:        {tag: `mi`, out: `Ψ`},
+	`omega`:      {tag: `mi`, out: `ω`},
+	`Omega`:      {tag: `mi`, out: `Ω`},
+
+	// Operators and relations.
+	`approx`:         {tag: `mo`, out: `≈`},
+	`ast`:            {tag: `mo`, out: `∗`},
+	`cap`:            {tag: `mo`, out: `∩`},
+	`cdot`:           {tag: `mo`, out: `⋅`},
+	`circ`:           {tag: `mo`, out: `∘`},
+	`cup`:            {tag: `mo`, out: `∪`},
+	`div`:            {tag: `mo`, out: `÷`},
+	`equiv`:          {tag: `mo`, out: `≡`},
+	`exists`:         {tag: `mo`, out: `∃`},
+	`forall`:         {tag: `mo`, out: `∀`},
+	`ge`:             {tag: `mo`, out: `≥`},
+	`geq`:            {tag: `mo`, out: `≥`},
+	`gets`:           {tag: `mo`, out: `←`},
+	`in`:             {tag: `mo`, out: `∈`},
+	`land`:           {tag: `mo`, out: `∧`},
+	`langle`:         {tag: `mo`, out: `⟨`},
+	`le`:             {tag: `mo`, out: `≤`},
+	`leftarrow`:      {tag: `mo`, out: `←`},
+	`Leftarrow`:      {tag: `mo`, out: `⇐`},
+	`leftrightarrow`: {tag: `mo`, out: `↔`},
+	`Leftrightarrow`: {tag: `mo`, out: `⇔`},
+	`leq`:            {tag: `mo`, out: `≤`},
+	`lor`:            {tag: `mo`, out: `∨`},
+	`mapsto`:         {tag: `mo`, out: `↦`},
+	`mid`:            {tag: `mo`, out: `∣`},
+	`mp`:             {tag: `mo`, out: `∓`},
+	`ne`:             {tag: `mo`, out: `≠`},
+	`neg`:            {tag: `mo`, out: `¬`},
+	`neq`:            {tag: `mo`, out: `≠`},
+	`notin`:          {tag: `mo`, out: `∉`},
+	`parallel`:       {tag: `mo`, out: `∥`},
+	`perp`:           {tag: `mo`, out: `⊥`},
+	`pm`:             {tag: `mo`, out: `±`},
+	`propto`:         {tag: `mo`, out: `∝`},
+	`rangle`:         {tag: `mo`, out: `⟩`},
+	`rightarrow`:     {tag: `mo`, out: `→`},
+	`Rightarrow`:     {tag: `mo`, out: `⇒`},
+	`sim`:            {tag: `mo`, out: `∼`},
+	`simeq`:          {tag: `mo`, out: `≃`},
+	`star`:           {tag: `mo`, out: `⋆`},
+	`subset`:         {tag: `mo`, out: `⊂`},
+	`subseteq`:       {tag: `mo`, out: `⊆`},
+	`supset`:         {tag: `mo`, out: `⊃`},
+	`supseteq`:       {tag: `mo`, out: `⊇`},
+	`times`:          {tag: `mo`, out: `×`},
+	`to`:             {tag: `mo`, out: `→`},
+	`{`:              {tag: `mo`, out: `{`},
+	`}`:              {tag: `mo`, out: `}`},
+	`|`:              {tag: `mo`, out: `∥`},
+
+	// Large operators.
+	`bigcap`: {tag: `mo`, out: `⋂`, isUnderOver: true},
+	`bigcup`: {tag: `mo`, out: `⋃`, isUnderOver: true},
+	`coprod`: {tag: `mo`, out: `∐`, isUnderOver: true},
+	`iint`:   {tag: `mo`, out: `∬`},
+	`int`:    {tag: `mo`, out: `∫`},
+	`oint`:   {tag: `mo`, out: `∮`},
+	`prod`:   {tag: `mo`, out: `∏`, isUnderOver: true},
+	`sum`:    {tag: `mo`, out: `∑`, isUnderOver: true},
+
+	// Functions.
+	`arccos`: {tag: `mi`, out: `arccos`},
+	`arcsin`: {tag: `mi`, out: `arcsin`},
+	`arctan`: {tag: `mi`, out: `arctan`},
+	`arg`:    {tag: `mi`, out: `arg`},
+	`cos`:    {tag: `mi`, out: `cos`},
+	`cosh`:   {tag: `mi`, out: `cosh`},
+	`cot`:    {tag: `mi`, out: `cot`},
+	`csc`:    {tag: `mi`, out: `csc`},
+	`deg`:    {tag: `mi`, out: `deg`},
+	`det`:    {tag: `mi`, out: `det`},
+	`dim`:    {tag: `mi`, out: `dim`},
+	`exp`:    {tag: `mi`, out: `exp`},
+	`gcd`:    {tag: `mi`, out: `gcd`},
+	`inf`:    {tag: `mo`, out: `inf`, isUnderOver: true},
+	`ker`:    {tag: `mi`, out: `ker`},
+	`lim`:    {tag: `mo`, out: `lim`, isUnderOver: true},
+	`ln`:     {tag: `mi`, out: `ln`},
+	`log`:    {tag: `mi`, out: `log`},
+	`max`:    {tag: `mo`, out: `max`, isUnderOver: true},
+	`min`:    {tag: `mo`, out: `min`, isUnderOver: true},
+	`sec`:    {tag: `mi`, out: `sec`},
+	`sin`:    {tag: `mi`, out: `sin`},
+	`sinh`:   {tag: `mi`, out: `sinh`},
+	`sup`:    {tag: `mo`, out: `sup`, isUnderOver: true},
+	`tan`:    {tag: `mi`, out: `tan`},
+	`tanh`:   {tag: `mi`, out: `tanh`},
+
+	// Miscellaneous symbols.
+	`aleph`:    {tag: `mi`, out: `ℵ`},
+	`angle`:    {tag: `mo`, out: `∠`},
+	`cdots`:    {tag: `mo`, out: `⋯`},
+	`ddots`:    {tag: `mo`, out: `⋱`},
+	`dots`:     {tag: `mo`, out: `…`},
+	`ell`:      {tag: `mi`, out: `ℓ`},
+	`emptyset`: {tag: `mi`, out: `∅`},
+	`hbar`:     {tag: `mi`, out: `ℏ`},
+	`infty`:    {tag: `mi`, out: `∞`},
+	`ldots`:    {tag: `mo`, out: `…`},
+	`nabla`:    {tag: `mi`, out: `∇`},
+	`partial`:  {tag: `mi`, out: `∂`},
+	`prime`:    {tag: `mo`, out: `′`},
+	`vdots`:    {tag: `mo`, out: `⋮`},
+
+	// Spaces.
+	`,`:     {kind: lmKindSpace, out: `0.167em`},
+	`:`:     {kind: lmKindSpace, out: `0.222em`},
+	`;`:     {kind: lmKindSpace, out: `0.278em`},
+	`!`:     {kind: lmKindSpace},
+	` `:     {kind: lmKindSpace, out: `0.333em`},
+	`quad`:  {kind: lmKindSpace, out: `1em`},
+	`qquad`: {kind: lmKindSpace, out: `2em`},
+
+	// Accents.
+	`bar`:       {kind: lmKindAccent, tag: `mover`, out: `¯`},
+	`ddot`:      {kind: lmKindAccent, tag: `mover`, out: `..`},
+	`dot`:       {kind: lmKindAccent, tag: `mover`, out: `.`},
+	`hat`:       {kind: lmKindAccent, tag: `mover`, out: `^`},
+	`overline`:  {kind: lmKindAccent, tag: `mover`, out: `¯`},
+	`tilde`:     {kind: lmKindAccent, tag: `mover`, out: `~`},
+	`underline`: {kind: lmKindAccent, tag: `munder`, out: `̲`},
+	`vec`:       {kind: lmKindAccent, tag: `mover`, out: `→`},
+	`widehat`:   {kind: lmKindAccent, tag: `mover`, out: `^`},
+
+	// Fonts.
+	`mathbb`:   {kind: lmKindFont, out: `double-struck`},
+	`mathbf`:   {kind: lmKindFont, out: `bold`},
+	`mathcal`:  {kind: lmKindFont, out: `script`},
+	`mathfrak`: {kind: lmKindFont, out: `fraktur`},
+	`mathit`:   {kind: lmKindFont, out: `italic`},
+	`mathsf`:   {kind: lmKindFont, out: `sans-serif`},
+	`mathtt`:   {kind: lmKindFont, out: `monospace`},
+
+	// Structures.
+	`binom`:  {kind: lmKindBinom},
+	`dfrac`:  {kind: lmKindFrac},
+	`frac`:   {kind: lmKindFrac},
+	`left`:   {kind: lmKindLeft},
+	`mathrm`: {kind: lmKindText},
+	`right`:  {kind: lmKindRight},
+	`sqrt`:   {kind: lmKindSqrt},
+	`text`:   {kind: lmKindText},
+	`textrm`: {kind: lmKindText},
+	`tfrac`:  {kind: lmKindFrac},
+}
+
+// lmParser convert the subset of LaTeX math notation into MathML.
+type lmParser struct {
+	in string
+	x  int
+}
+
+// latexmathToMathML convert the LaTeX math expression into the content of
+// MathML element "math".
+func latexmathToMathML(in string) string {
+	var lm = lmParser{
+		in: in,
+	}
+	return lm.parseExpr(0)
+}
+
+// skipSpaces skip the white spaces.
+func (lm *lmParser) skipSpaces() {
+	for lm.x < len(lm.in) && ascii.IsSpace(lm.in[lm.x]) {
+		lm.x++
+	}
+}
+
+// parseExpr parse the expression until the end of input, or until the
+// character end, or until the command "\right".
+func (lm *lmParser) parseExpr(end byte) string {
+	var (
+		out  strings.Builder
+		node mathNode
+		c    byte
+	)
+
+	for {
+		lm.skipSpaces()
+		if lm.x >= len(lm.in) {
+			break
+		}
+		c = lm.in[lm.x]
+		if end != 0 && c == end {
+			break
+		}
+		if strings.HasPrefix(lm.in[lm.x:], `\right`) {
+			break
+		}
+		node = lm.parseScripts()
+		out.WriteString(node.mml)
+	}
+	return out.String()
+}
+
+// parseScripts parse the atom with optional sub-script and super-script,
+// in any order.
+func (lm *lmParser) parseScripts() (node mathNode) {
+	var (
+		base = lm.parseAtom()
+
+		sub   mathNode
+		sup   mathNode
+		isSub bool
+		isSup bool
+	)
+
+	for {
+		lm.skipSpaces()
+		if lm.x >= len(lm.in) {
+			break
+		}
+		switch lm.in[lm.x] {
+		case '_':
+			if isSub {
+				break
+			}
+			lm.x++
+			sub = lm.parseArg()
+			isSub = true
+			continue
+		case '^':
+			if isSup {
+				break
+			}
+			lm.x++
+			sup = lm.parseArg()
+			isSup = true
+			continue
+		case '\'':
+			lm.x++
+			base = mathNode{mml: `<msup>` + base.mml + mathTag(`mo`, `′`) + `</msup>`}
+			continue
+		}
+		break
+	}
+
+	var under, over = `msub`, `msup`
+	if base.isUnderOver {
+		under, over = `munder`, `mover`
+	}
+	switch {
+	case isSub && isSup:
+		if base.isUnderOver {
+			return mathNode{mml: `<munderover>` + base.mml + sub.inner() + sup.inner() + `</munderover>`}
+		}
+		return mathNode{mml: `<msubsup>` + base.mml + sub.inner() + sup.inner() + `</msubsup>`}
+	case isSub:
+		return mathNode{mml: `<` + under + `>` + base.mml + sub.inner() + `</` + under + `>`}
+	case isSup:
+		return mathNode{mml: `<` + over + `>` + base.mml + sup.inner() + `</` + over + `>`}
+	}
+	return base
+}
+
+// parseArg parse the argument of command or script, either the group
+// "{...}" or single atom.
+func (lm *lmParser) parseArg() (node mathNode) {
+	lm.skipSpaces()
+	if lm.x >= len(lm.in) {
+		return node
+	}
+	if lm.in[lm.x] != '{' {
+		return lm.parseAtom()
+	}
+	lm.x++
+	node.body = lm.parseExpr('}')
+	node.isBracket = true
+	node.mml = `<mrow>` + node.body + `</mrow>`
+	if lm.x < len(lm.in) {
+		lm.x++
+	}
+	return node
+}
+
+// parseRaw return the raw text inside the group "{...}".
+func (lm *lmParser) parseRaw() string {
+	lm.skipSpaces()
+	if lm.x >= len(lm.in) || lm.in[lm.x] != '{' {
+		return ``
+	}
+
+	var x = strings.IndexByte(lm.in[lm.x:], '}')
+	if x < 0 {
+		x = len(lm.in) - lm.x
+	}
+
+	var raw = lm.in[lm.x+1 : lm.x+x]
+
+	lm.x = min(lm.x+x+1, len(lm.in))
+	return raw
+}
+
+// parseAtom parse single number, identifier, operator, group, or command.
+func (lm *lmParser) parseAtom() (node mathNode) {
+	var (
+		c = lm.in[lm.x]
+		x int
+	)
+
+	switch {
+	case c == '{':
+		return lm.parseArg()
+
+	case c == '\\':
+		return lm.parseCommand()
+
+	case ascii.IsDigit(c):
+		x = lm.x + 1
+		for x < len(lm.in) && (ascii.IsDigit(lm.in[x]) ||
+			(lm.in[x] == '.' && x+1 < len(lm.in) && ascii.IsDigit(lm.in[x+1]))) {
+			x++
+		}
+		node.mml = mathTag(`mn`, lm.in[lm.x:x])
+		lm.x = x
+		return node
+
+	case ascii.IsAlpha(c):
+		lm.x++
+		return mathNode{mml: mathTag(`mi`, string(c))}
+	}
+
+	// Consume one UTF-8 character as operator.
+	x = lm.x + 1
+	for x < len(lm.in) && lm.in[x]&0xC0 == 0x80 {
+		x++
+	}
+	node.mml = mathTag(`mo`, mathEscape(lm.in[lm.x:x]))
+	lm.x = x
+	return node
+}
+
+// parseCommand parse the command that start with backslash.
+func (lm *lmParser) parseCommand() (node mathNode) {
+	var x = lm.x + 1
+
+	for x < len(lm.in) && ascii.IsAlpha(lm.in[x]) {
+		x++
+	}
+	if x == lm.x+1 && x < len(lm.in) {
+		// Command with single non-alphabet character, for
+		// example "\," or "\{".
+		x++
+	}
+
+	var (
+		name = lm.in[lm.x+1 : x]
+
+		cmd lmCommand
+		ok  bool
+	)
+
+	lm.x = x
+
+	cmd, ok = lmCommands[name]
+	if !ok {
+		return mathNode{mml: `<merror>` + mathTag(`mtext`, mathEscape(`\`+name)) + `</merror>`}
+	}
+
+	switch cmd.kind {
+	case lmKindAccent:
+		var arg = lm.parseArg()
+		return mathNode{mml: `<` + cmd.tag + ` accent="true">` + arg.inner() +
+			mathTag(`mo`, cmd.out) + `</` + cmd.tag + `>`}
+
+	case lmKindFont:
+		var arg = lm.parseArg()
+		return mathNode{mml: `<mstyle mathvariant="` + cmd.out + `">` + arg.inner() + `</mstyle>`}
+
+	case lmKindFrac:
+		var (
+			num = lm.parseArg()
+			den = lm.parseArg()
+		)
+		return mathNode{mml: `<mfrac>` + num.inner() + den.inner() + `</mfrac>`}
+
+	case lmKindBinom:
+		var (
+			n = lm.parseArg()
+			k = lm.parseArg()
+		)
+		return mathNode{mml: `<mrow>` + mathTag(`mo`, `(`) +
+			`<mfrac linethickness="0">` + n.inner() + k.inner() + `</mfrac>` +
+			mathTag(`mo`, `)`) + `</mrow>`}
+
+	case lmKindSqrt:
+		lm.skipSpaces()
+		if lm.x < len(lm.in) && lm.in[lm.x] == '[' {
+			lm.x++
+			var index = lm.parseExpr(']')
+			if lm.x < len(lm.in) {
+				lm.x++
+			}
+			var arg = lm.parseArg()
+			return mathNode{mml: `<mroot>` + arg.inner() + `<mrow>` + index + `</mrow></mroot>`}
+		}
+		var arg = lm.parseArg()
+		return mathNode{mml: `<msqrt>` + arg.inner() + `</msqrt>`}
+
+	case lmKindSpace:
+		if len(cmd.out) == 0 {
+			return node
+		}
+		return mathNode{mml: `<mspace width="` + cmd.out + `"/>`}
+
+	case lmKindText:
+		return mathNode{mml: mathTag(`mtext`, mathEscape(lm.parseRaw()))}
+
+	case lmKindLeft:
+		return lm.parseLeftRight()
+
+	case lmKindRight:
+		// Unmatched "\right".
+		lm.parseDelimiter()
+		return node
+	}
+
+	return mathNode{
+		mml:         mathTag(cmd.tag, cmd.out),
+		isUnderOver: cmd.isUnderOver,
+	}
+}
+
+// parseLeftRight parse the expression "\left" DELIM ... "\right" DELIM.
+func (lm *lmParser) parseLeftRight() (node mathNode) {
+	var (
+		left  = lm.parseDelimiter()
+		body  = lm.parseExpr(0)
+		right string
+	)
+
+	if strings.HasPrefix(lm.in[lm.x:], `\right`) {
+		lm.x += len(`\right`)
+		right = lm.parseDelimiter()
+	}
+
+	node.body = body
+	node.isBracket = true
+	if len(left) > 0 {
+		body = mathTag(`mo`, left) + body
+	}
+	if len(right) > 0 {
+		body += mathTag(`mo`, right)
+	}
+	node.mml = `<mrow>` + body + `</mrow>`
+	return node
+}
+
+// parseDelimiter parse the delimiter after "\left" or "\right".
+// The delimiter "." is returned as empty string.
+func (lm *lmParser) parseDelimiter() string {
+	lm.skipSpaces()
+	if lm.x >= len(lm.in) {
+		return ``
+	}
+
+	var c = lm.in[lm.x]
+
+	if c == '\\' {
+		var node = lm.parseCommand()
+		return strings.TrimSuffix(strings.TrimPrefix(node.mml, `<mo>`), `</mo>`)
+	}
+	lm.x++
+	if c == '.' {
+		return ``
+	}
+	return mathEscape(string(c))
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+)
+
+func TestAsciimathToMathML(t *testing.T) {
+	type testCase struct {
+		in  string
+		exp string
+	}
+
+	var cases = []testCase{{
+		in:  `a/b`,
+		exp: `<mfrac><mi>a</mi><mi>b</mi></mfrac>`,
+	}, {
+		in:  `(a+b)/c`,
+		exp: `<mfrac><mrow><mi>a</mi><mo>+</mo><mi>b</mi></mrow><mi>c</mi></mfrac>`,
+	}, {
+		in:  `root(3)(x)`,
+		exp: `<mroot><mrow><mi>x</mi></mrow><mrow><mn>3</mn></mrow></mroot>`,
+	}, {
+		in:  `lim_(x->0) 1.5`,
+		exp: `<munder><mo>lim</mo><mrow><mi>x</mi><mo>→</mo><mn>0</mn></mrow></munder><mn>1.5</mn>`,
+	}, {
+		in:  `text(if a<b) "c & d"`,
+		exp: `<mtext>if a&lt;b</mtext><mtext>c &amp; d</mtext>`,
+	}, {
+		in:  `hat(x) bb(y)`,
+		exp: `<mover accent="true"><mrow><mi>x</mi></mrow><mo>^</mo></mover><mstyle mathvariant="bold"><mrow><mi>y</mi></mrow></mstyle>`,
+	}, {
+		in:  `x_i^2 alpha RR`,
+		exp: `<msubsup><mi>x</mi><mi>i</mi><mn>2</mn></msubsup><mi>α</mi><mi>ℝ</mi>`,
+	}, {
+		in:  `x)`,
+		exp: `<mi>x</mi><mo>)</mo>`,
+	}}
+
+	var (
+		c   testCase
+		got string
+	)
+	for _, c = range cases {
+		got = asciimathToMathML(c.in)
+		test.Assert(t, c.in, c.exp, got)
+	}
+}
+
+func TestLatexmathToMathML(t *testing.T) {
+	type testCase struct {
+		in  string
+		exp string
+	}
+
+	var cases = []testCase{{
+		in:  `\binom{n}{k}`,
+		exp: `<mrow><mo>(</mo><mfrac linethickness="0"><mrow><mi>n</mi></mrow><mrow><mi>k</mi></mrow></mfrac><mo>)</mo></mrow>`,
+	}, {
+		in:  `\sqrt[3]{x}`,
+		exp: `<mroot><mrow><mi>x</mi></mrow><mrow><mn>3</mn></mrow></mroot>`,
+	}, {
+		in:  `\text{if } x<0`,
+		exp: `<mtext>if </mtext><mi>x</mi><mo>&lt;</mo><mn>0</mn>`,
+	}, {
+		in:  `\sum_{i=1}^{n} i`,
+		exp: `<munderover><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mrow><mi>n</mi></mrow></munderover><mi>i</mi>`,
+	}, {
+		in:  `f'(x)`,
+		exp: `<msup><mi>f</mi><mo>′</mo></msup><mo>(</mo><mi>x</mi><mo>)</mo>`,
+	}, {
+		in:  `\left\{ x \right.`,
+		exp: `<mrow><mo>{</mo><mi>x</mi></mrow>`,
+	}, {
+		in:  `a \cdot b \leq c`,
+		exp: `<mi>a</mi><mo>⋅</mo><mi>b</mi><mo>≤</mo><mi>c</mi>`,
+	}, {
+		in:  `\unknown`,
+		exp: `<merror><mtext>\unknown</mtext></merror>`,
+	}}
+
+	var (
+		c   testCase
+		got string
+	)
+	for _, c = range cases {
+		got = latexmathToMathML(c.in)
+		test.Assert(t, c.in, c.exp, got)
+	}
+}
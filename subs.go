@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"bytes"
+	"strings"
+)
+
+// _subsNames contains mapping of substitution name in the "subs" attribute
+// and its passthrough substitutions.
+// The "callouts" is always applied on verbatim block, so it does not have
+// any substitution.
+var _subsNames = map[string]int{
+	`attributes`:        passSubAttr,
+	`callouts`:          passSubNone,
+	`macros`:            passSubMacro,
+	`none`:              passSubNone,
+	`normal`:            passSubNormal,
+	`post_replacements`: passSubPostRepl,
+	`quotes`:            passSubQuote,
+	`replacements`:      passSubRepl,
+	`specialchars`:      passSubChar,
+	`specialcharacters`: passSubChar,
+	`verbatim`:          passSubVerbatim,
+}
+
+// parseSubs parse the value of "subs" attribute,
+//
+//	SUBS     = SUB *("," SUB)
+//
+//	SUB      = [ "+" / "-" ] SUB_NAME [ "+" ]
+//
+// The SUB_NAME with "+" or "-" modifier add or remove the substitution
+// from the default substitutions defSubs.
+// The SUB_NAME without modifier replace the default substitutions.
+// The unknown SUB_NAME is ignored.
+func parseSubs(raw string, defSubs int) (subs int) {
+	var (
+		isSet bool
+		name  string
+		sub   int
+		ok    bool
+	)
+	for _, name = range strings.Split(raw, `,`) {
+		name = strings.TrimSpace(name)
+
+		var op byte
+		switch {
+		case strings.HasPrefix(name, `+`), strings.HasPrefix(name, `-`):
+			op = name[0]
+			name = name[1:]
+		case strings.HasSuffix(name, `+`):
+			op = '+'
+			name = name[:len(name)-1]
+		}
+
+		sub, ok = _subsNames[strings.TrimSpace(name)]
+		if !ok {
+			continue
+		}
+		if !isSet {
+			isSet = true
+			if op != 0 {
+				subs = defSubs
+			}
+		}
+		switch op {
+		case '-':
+			subs &^= sub
+		default:
+			subs |= sub
+		}
+	}
+	if !isSet {
+		return defSubs
+	}
+	return subs
+}
+
+// subs return the substitutions for the content of element based on its
+// "subs" attribute, or defSubs if the attribute is not set.
+func (el *element) subs(defSubs int) int {
+	var raw, ok = el.Attrs[attrNameSubs]
+	if !ok {
+		return defSubs
+	}
+	return parseSubs(raw, defSubs)
+}
+
+// applyVerbatimSubs apply the substitutions to the content of verbatim
+// block, like listing and literal, based on the "subs" attribute.
+// The default substitutions for verbatim block is "verbatim", only the
+// special characters are replaced.
+func (el *element) applyVerbatimSubs(doc *Document) {
+	el.raw = bytes.TrimRight(el.raw, " \n")
+	el.applySubs = el.subs(passSubVerbatim)
+	el.raw = htmlSubs(doc, el)
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package asciidoctor
+
+import (
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+)
+
+func TestParseSubs(t *testing.T) {
+	type testCase struct {
+		raw     string
+		defSubs int
+		exp     int
+	}
+
+	var cases = []testCase{{
+		raw:     `attributes+`,
+		defSubs: passSubVerbatim,
+		exp:     passSubChar | passSubAttr,
+	}, {
+		raw:     `-replacements`,
+		defSubs: passSubNormal,
+		exp:     passSubNormal &^ passSubRepl,
+	}, {
+		raw:     `quotes, macros`,
+		defSubs: passSubNormal,
+		exp:     passSubQuote | passSubMacro,
+	}, {
+		raw:     `none`,
+		defSubs: passSubNormal,
+		exp:     passSubNone,
+	}, {
+		raw:     `unknown`,
+		defSubs: passSubVerbatim,
+		exp:     passSubVerbatim,
+	}}
+
+	var c testCase
+	for _, c = range cases {
+		test.Assert(t, c.raw, c.exp, parseSubs(c.raw, c.defSubs))
+	}
+}
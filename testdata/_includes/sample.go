@@ -0,0 +1,13 @@
+package sample
+
+import "fmt"
+
+// Hello print greeting.
+func Hello(name string) {
+	fmt.Printf("Hello, %s!\n", name)
+}
+
+// Bye print farewell.
+func Bye(name string) {
+	fmt.Printf("Bye, %s!\n", name)
+}
@@ -0,0 +1,18 @@
+package tagged
+
+import "fmt"
+
+// tag::hello[]
+func Hello() {
+	// tag::print[]
+	fmt.Println("Hello")
+	// end::print[]
+}
+
+// end::hello[]
+// tag::bye[]
+func Bye() {
+	fmt.Println("Bye")
+}
+
+// end::bye[]